@@ -22,26 +22,88 @@ func (s *Server) loadRoutes(handlers *handlers.Handler, appMiddleware *middlewar
 
 	// Canvas routes
 	r.Route("/canvas", func(r chi.Router) {
-		r.Use(middleware.AllowContentType("application/json"))
+		r.Use(middleware.AllowContentType("application/json", "multipart/form-data")) // Images are imported as multipart forms
 		r.Use(appMiddleware.Authorize)
-		r.Use(appMiddleware.AuthorizeCanvasAccess)
 
 		r.Post("/create", handlers.PostCreateCanvas)
-		r.Delete("/{id}/delete", handlers.DeleteCanvas)
-		r.Put("/{id}/update", handlers.PutUpdateCanvas)
-		r.Get("/{id}", handlers.GetCanvas)
+		r.Post("/import", handlers.PostImportCanvas)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Use(appMiddleware.AuthorizeCanvasAccess)
+
+			r.Delete("/delete", handlers.DeleteCanvas)
+			r.Put("/update", handlers.PutUpdateCanvas)
+			r.Get("/", handlers.GetCanvas)
+			r.Get("/tiles/{x}/{y}", handlers.GetTile)
+
+			// Layer routes
+			r.Route("/layers", func(r chi.Router) {
+				r.Get("/", handlers.GetLayers)
+				r.Post("/", handlers.PostCreateLayer)
+				r.Put("/order", handlers.PutReorderLayers)
+				r.Put("/{layerID}", handlers.PutUpdateLayer)
+				r.Delete("/{layerID}", handlers.DeleteLayer)
+			})
+
+			// Palette routes
+			r.Route("/palette", func(r chi.Router) {
+				r.Get("/", handlers.GetPalette)
+				r.Put("/", handlers.PutReplacePalette)
+				r.Post("/", handlers.PostAddPaletteColor)
+				r.Put("/lock", handlers.PutPaletteLock)
+				r.Put("/{index}", handlers.PutUpdatePaletteColor)
+				r.Delete("/{index}", handlers.DeletePaletteColor)
+			})
+
+			// Chat routes
+			r.Route("/chat", func(r chi.Router) {
+				r.Get("/", handlers.GetChatMessages)
+				r.Put("/settings", handlers.PutChatSettings)
+			})
+
+			// Comment routes
+			r.Route("/comments", func(r chi.Router) {
+				r.Get("/", handlers.GetComments)
+				r.Post("/", handlers.PostCreateComment)
+				r.Get("/{commentID}", handlers.GetCommentThread)
+				r.Put("/{commentID}", handlers.PutUpdateComment)
+				r.Delete("/{commentID}", handlers.DeleteComment)
+				r.Post("/{commentID}/replies", handlers.PostCreateReply)
+				r.Put("/{commentID}/resolve", handlers.PutResolveComment)
+			})
+
+			// Version routes
+			r.Route("/versions", func(r chi.Router) {
+				r.Get("/", handlers.GetVersions)
+				r.Post("/", handlers.PostCreateVersion)
+				r.Get("/{versionID}", handlers.GetVersion)
+				r.Post("/{versionID}/restore", handlers.PostRestoreVersion)
+			})
+
+			r.Get("/blame", handlers.GetBlame)
+			r.Get("/contributions", handlers.GetContributions)
+			r.Get("/replay", handlers.GetReplay)
+			r.Get("/timelapse.gif", handlers.GetTimelapse)
+			r.Get("/export.png", handlers.GetExportPNG)
+			r.Post("/import", handlers.PostImportImage)
+		})
 	})
 
 	// User access routes
+	// The global access route used to be PUT /access/, it never had a canvas ID so it moved to PUT /access/{id}/global
 	r.Route("/access", func(r chi.Router) {
 		r.Use(middleware.AllowContentType("application/json"))
 		r.Use(appMiddleware.Authorize)
 
-		r.Post("/{id}/create", handlers.PostCreateAccess)
-		r.Post("/{id}/delete", handlers.PostDeleteAccess)
-		r.Put("/{id}/update", handlers.PutUpdateAccess)
-		r.Get("/{id}", handlers.GetAccessRules)
-		r.Put("/", handlers.PutUpdateGlobalAccess)
+		r.Route("/{id}", func(r chi.Router) {
+			r.Use(appMiddleware.AuthorizeCanvasAccess)
+
+			r.Post("/create", handlers.PostCreateAccess)
+			r.Post("/delete", handlers.PostDeleteAccess)
+			r.Put("/update", handlers.PutUpdateAccess)
+			r.Put("/global", handlers.PutUpdateGlobalAccess)
+			r.Get("/", handlers.GetAccessRules)
+		})
 	})
 
 	return r
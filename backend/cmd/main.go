@@ -13,6 +13,8 @@ import (
 )
 
 func main() {
+	config.Load()
+
 	fmt.Println(config.PixioLogo)
 	fmt.Println("Pixio API - Version 1.0.0")
 
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,7 +11,9 @@ import (
 	"github.com/joho/godotenv"
 )
 
-func init() {
+// Load reads the configuration from the .env file and the environment, it must be called before the server starts.
+// Packages that are only tested don't call it and use the defaults.
+func Load() {
 	err := godotenv.Load()
 	if err != nil {
 		log.Fatalf("Error loading .env file %s", err)
@@ -20,6 +23,54 @@ func init() {
 	AccessTokenSecret = os.Getenv("ACCESS_TOKEN_SECRET")
 	RefreshTokenSecret = os.Getenv("REFRESH_TOKEN_SECRET")
 	AllowedDomains = strings.Split(os.Getenv("ALLOWED_DOMAINS"), ",")
+	RoomFlushInterval = getEnvDuration("ROOM_FLUSH_INTERVAL", RoomFlushInterval)
+	RoomDeleteDelay = getEnvDuration("ROOM_DELETE_DELAY", RoomDeleteDelay)
+	RoomEvictRetries = getEnvInt("ROOM_EVICT_RETRIES", RoomEvictRetries)
+	WSSendQueueSize = getEnvInt("WS_SEND_QUEUE_SIZE", WSSendQueueSize)
+	WSPingInterval = getEnvDuration("WS_PING_INTERVAL", WSPingInterval)
+	WSPongTimeout = getEnvDuration("WS_PONG_TIMEOUT", WSPongTimeout)
+	WSMaxMessageSize = int64(getEnvInt("WS_MAX_MESSAGE_SIZE", int(WSMaxMessageSize)))
+	RoomOpLogSize = getEnvInt("ROOM_OP_LOG_SIZE", RoomOpLogSize)
+	RoomUndoHistorySize = getEnvInt("ROOM_UNDO_HISTORY_SIZE", RoomUndoHistorySize)
+	RoomCursorInterval = getEnvDuration("ROOM_CURSOR_INTERVAL", RoomCursorInterval)
+	VersionEditInterval = getEnvInt("VERSION_EDIT_INTERVAL", VersionEditInterval)
+	VersionTimeInterval = getEnvDuration("VERSION_TIME_INTERVAL", VersionTimeInterval)
+	EditScanMaxBatches = getEnvInt("EDIT_SCAN_MAX_BATCHES", EditScanMaxBatches)
+	TimelapseMaxFrames = getEnvInt("TIMELAPSE_MAX_FRAMES", TimelapseMaxFrames)
+	ToolMaxBrushSize = getEnvInt("TOOL_MAX_BRUSH_SIZE", ToolMaxBrushSize)
+	ToolMaxStampPoints = getEnvInt("TOOL_MAX_STAMP_POINTS", ToolMaxStampPoints)
+	ChatRateLimit = getEnvInt("CHAT_RATE_LIMIT", ChatRateLimit)
+	ChatRateInterval = getEnvDuration("CHAT_RATE_INTERVAL", ChatRateInterval)
+}
+
+// getEnvDuration reads a duration (e.g. "30s", "5m") from the environment, returning the fallback when it is not set.
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid duration for %s: %s", key, err)
+	}
+
+	return duration
+}
+
+// getEnvInt reads an integer from the environment, returning the fallback when it is not set.
+func getEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid integer for %s: %s", key, err)
+	}
+
+	return number
 }
 
 var (
@@ -45,4 +96,30 @@ var (
 
 	SessionExpiration     = time.Hour * 24 * 30 // 30 days
 	AccessTokenExpiration = time.Minute * 15    // 15 minutes
+	RoomFlushInterval     = time.Second * 30    // How often live room pixel data is written back to the database
+	RoomDeleteDelay       = time.Minute * 3     // How long an empty room is kept in memory before being evicted
+	RoomEvictRetries      = 5                   // Number of times saving an empty room is attempted before it is evicted with its unsaved changes
+	RoomOpLogSize         = 1000                // Number of recent operations kept per room for reconnecting clients
+	RoomUndoHistorySize   = 100                 // Number of strokes each user can undo in a room
+	RoomCursorInterval    = time.Second / 20    // How often pending cursor positions are broadcast to the room (20 Hz)
+	VersionEditInterval   = 500                 // Number of edits in a room after which a version is saved automatically
+	VersionTimeInterval   = time.Minute * 10    // Time after the last version a room with new edits saves a version automatically
+	EditScanMaxBatches    = 10000               // Maximum number of the most recent edit batches read for a blame or contributions request
+	TimelapseMaxFrames    = 300                 // Maximum number of frames of a timelapse, longer intervals are used for canvases with a longer history
+	TimelapseMaxSize      = 2048                // Maximum width and height in pixels of a scaled timelapse
+	ExportMaxScale        = 32                  // Maximum scale of an exported image
+	ExportMaxSize         = 4096                // Maximum width and height in pixels of an exported image
+	ImportMaxUploadSize   = int64(10 << 20)     // Maximum size in bytes of an imported image file
+	ImportMaxSourceSize   = 4096                // Maximum width and height in pixels of an imported image before it is resized
+	ToolMaxBrushSize      = 64                  // Maximum size in pixels of a brush stamp
+	ToolMaxStampPoints    = 1024                // Maximum number of points in a single brush stamp message
+	ChatMaxLength         = 500                 // Maximum number of characters in a chat message
+	ChatRateLimit         = 5                   // Number of chat messages a user can send in a room per ChatRateInterval
+	ChatRateInterval      = time.Second * 10    // Window in which ChatRateLimit applies
+	ChatHistoryPageSize   = 50                  // Default and maximum number of chat messages returned per history page
+	WSSendQueueSize       = 256                 // Maximum number of outbound messages queued per websocket connection
+	WSPingInterval        = time.Second * 25    // How often connections are pinged, must be lower than WSPongTimeout
+	WSPongTimeout         = time.Second * 60    // Connections that don't respond within this time are considered dead
+	WSAuthTimeout         = time.Second * 10    // Time a new connection has to send the auth message
+	WSMaxMessageSize      = int64(1 << 20)      // Maximum size in bytes of an incoming websocket message (1 MB)
 )
@@ -91,6 +91,9 @@ func (q *Queries) GetCanvas(canvasID string) (types.Canvas, error) {
 		&canvas.LinkAccessRole,
 		&canvas.CreatedAt,
 		&canvas.StarCount,
+		&canvas.Palette,
+		&canvas.PaletteLocked,
+		&canvas.ViewerChat,
 	)
 
 	return canvas, err
@@ -0,0 +1,75 @@
+package data
+
+import (
+	"context"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+)
+
+func (q *Queries) CreateChatMessage(canvasID, userID, content string) (types.ChatMessage, error) {
+	query := `
+		INSERT INTO chat_messages (message_id, canvas_id, user_id, content) VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+
+	message := types.ChatMessage{
+		ID:       utils.GenerateID(),
+		CanvasID: canvasID,
+		UserID:   userID,
+		Content:  content,
+	}
+	err := q.pool.QueryRow(context.Background(), query, message.ID, canvasID, userID, content).Scan(&message.CreatedAt)
+	return message, err
+}
+
+// GetChatMessages returns up to limit messages of the canvas older than the before cursor, newest first.
+// An empty cursor starts from the most recent message.
+func (q *Queries) GetChatMessages(canvasID, before string, limit int) ([]types.ChatMessage, error) {
+	query := `
+		SELECT m.message_id, m.canvas_id, m.user_id, u.username, u.avatar_url, m.content, m.created_at
+		FROM chat_messages m
+		JOIN users u ON u.user_id = m.user_id
+		WHERE m.canvas_id = $1 AND ($2::text = '' OR m.message_id < $2::text)
+		ORDER BY m.message_id DESC
+		LIMIT $3
+	`
+
+	messages := []types.ChatMessage{}
+	rows, err := q.pool.Query(context.Background(), query, canvasID, before, limit)
+	if err != nil {
+		return messages, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var message types.ChatMessage
+		err = rows.Scan(
+			&message.ID,
+			&message.CanvasID,
+			&message.UserID,
+			&message.Username,
+			&message.AvatarURL,
+			&message.Content,
+			&message.CreatedAt,
+		)
+		if err != nil {
+			return messages, err
+		}
+
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (q *Queries) UpdateViewerChat(canvasID string, enabled bool) error {
+	query := `UPDATE canvases SET viewer_chat = $1 WHERE canvas_id = $2`
+
+	_, err := q.pool.Exec(context.Background(), query, enabled, canvasID)
+	return err
+}
@@ -0,0 +1,198 @@
+package data
+
+import (
+	"context"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+const commentColumns = `
+	c.comment_id, c.canvas_id, c.parent_id, c.user_id, u.username, u.avatar_url, c.content,
+	c.x, c.y, c.width, c.height, c.resolved, c.resolved_by, c.resolved_at, c.created_at, c.updated_at
+`
+
+func scanComment(row pgx.Row) (types.Comment, error) {
+	var comment types.Comment
+	err := row.Scan(
+		&comment.ID,
+		&comment.CanvasID,
+		&comment.ParentID,
+		&comment.UserID,
+		&comment.Username,
+		&comment.AvatarURL,
+		&comment.Content,
+		&comment.X,
+		&comment.Y,
+		&comment.Width,
+		&comment.Height,
+		&comment.Resolved,
+		&comment.ResolvedBy,
+		&comment.ResolvedAt,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+	return comment, err
+}
+
+// groupThreads nests the replies under their thread, keeping the order of the comments.
+func groupThreads(comments []types.Comment) []types.Comment {
+	threads := []types.Comment{}
+	index := make(map[string]int)
+	for _, comment := range comments {
+		if comment.ParentID == "" {
+			index[comment.ID] = len(threads)
+			threads = append(threads, comment)
+		}
+	}
+
+	for _, comment := range comments {
+		if i, ok := index[string(comment.ParentID)]; ok {
+			threads[i].Replies = append(threads[i].Replies, comment)
+		}
+	}
+
+	return threads
+}
+
+func (q *Queries) queryComments(query string, args ...any) ([]types.Comment, error) {
+	comments := []types.Comment{}
+	rows, err := q.pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return comments, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return comments, err
+		}
+
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// GetComments returns every comment thread of the canvas with its replies, oldest first.
+func (q *Queries) GetComments(canvasID string) ([]types.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments c JOIN users u ON u.user_id = c.user_id WHERE c.canvas_id = $1 ORDER BY c.comment_id`
+
+	comments, err := q.queryComments(query, canvasID)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupThreads(comments), nil
+}
+
+// GetCommentThread returns the comment thread with its replies. Returns pgx.ErrNoRows if commentID is not a thread of the canvas.
+func (q *Queries) GetCommentThread(canvasID, commentID string) (types.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + ` FROM comments c JOIN users u ON u.user_id = c.user_id
+		WHERE c.canvas_id = $1 AND ((c.comment_id = $2 AND c.parent_id IS NULL) OR c.parent_id = $2)
+		ORDER BY c.comment_id
+	`
+
+	comments, err := q.queryComments(query, canvasID, commentID)
+	if err != nil {
+		return types.Comment{}, err
+	}
+
+	threads := groupThreads(comments)
+	if len(threads) == 0 {
+		return types.Comment{}, pgx.ErrNoRows
+	}
+
+	return threads[0], nil
+}
+
+// GetComment returns a single comment or reply without its replies.
+func (q *Queries) GetComment(canvasID, commentID string) (types.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments c JOIN users u ON u.user_id = c.user_id WHERE c.canvas_id = $1 AND c.comment_id = $2`
+
+	return scanComment(q.pool.QueryRow(context.Background(), query, canvasID, commentID))
+}
+
+// CreateComment starts a new comment thread anchored to the canvas.
+func (q *Queries) CreateComment(canvasID, userID, content string, x, y, width, height int) (types.Comment, error) {
+	query := `
+		WITH c AS (
+			INSERT INTO comments (comment_id, canvas_id, user_id, content, x, y, width, height)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING *
+		)
+		SELECT ` + commentColumns + ` FROM c JOIN users u ON u.user_id = c.user_id
+	`
+
+	return scanComment(q.pool.QueryRow(context.Background(), query, utils.GenerateID(), canvasID, userID, content, x, y, width, height))
+}
+
+// CreateReply adds a reply to the comment thread, copying the anchor of the thread.
+// Returns pgx.ErrNoRows if parentID is not a thread of the canvas.
+func (q *Queries) CreateReply(canvasID, parentID, userID, content string) (types.Comment, error) {
+	query := `
+		WITH c AS (
+			INSERT INTO comments (comment_id, canvas_id, parent_id, user_id, content, x, y, width, height)
+			SELECT $1, canvas_id, comment_id, $4, $5, x, y, width, height FROM comments
+			WHERE canvas_id = $2 AND comment_id = $3 AND parent_id IS NULL
+			RETURNING *
+		)
+		SELECT ` + commentColumns + ` FROM c JOIN users u ON u.user_id = c.user_id
+	`
+
+	return scanComment(q.pool.QueryRow(context.Background(), query, utils.GenerateID(), canvasID, parentID, userID, content))
+}
+
+func (q *Queries) UpdateCommentContent(canvasID, commentID, content string) (types.Comment, error) {
+	query := `
+		WITH c AS (
+			UPDATE comments SET content = $1, updated_at = now()
+			WHERE canvas_id = $2 AND comment_id = $3
+			RETURNING *
+		)
+		SELECT ` + commentColumns + ` FROM c JOIN users u ON u.user_id = c.user_id
+	`
+
+	return scanComment(q.pool.QueryRow(context.Background(), query, content, canvasID, commentID))
+}
+
+// ResolveComment marks the comment thread as resolved by the user, or reopens it.
+// Returns pgx.ErrNoRows if commentID is not a thread of the canvas.
+func (q *Queries) ResolveComment(canvasID, commentID, userID string, resolved bool) (types.Comment, error) {
+	query := `
+		WITH c AS (
+			UPDATE comments SET
+				resolved = $1,
+				resolved_by = CASE WHEN $1 THEN $2::text END,
+				resolved_at = CASE WHEN $1 THEN now() END
+			WHERE canvas_id = $3 AND comment_id = $4 AND parent_id IS NULL
+			RETURNING *
+		)
+		SELECT ` + commentColumns + ` FROM c JOIN users u ON u.user_id = c.user_id
+	`
+
+	return scanComment(q.pool.QueryRow(context.Background(), query, resolved, userID, canvasID, commentID))
+}
+
+// DeleteComment deletes the comment, deleting a thread also deletes its replies.
+func (q *Queries) DeleteComment(canvasID, commentID string) error {
+	query := `DELETE FROM comments WHERE canvas_id = $1 AND comment_id = $2`
+
+	tag, err := q.pool.Exec(context.Background(), query, canvasID, commentID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return types.ErrCommentNotFound
+	}
+
+	return nil
+}
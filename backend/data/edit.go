@@ -0,0 +1,71 @@
+package data
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateEdits stores the edit batches against the latest version of the canvas.
+func (q *Queries) CreateEdits(canvasID string, edits []types.Edit) error {
+	ctx := context.Background()
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, edit := range edits {
+		batch.Queue(`
+			INSERT INTO edits (edit_id, canvas_id, version_id, user_id, pixel_count, data, timestamp)
+			VALUES ($1, $2, (SELECT version_id FROM versions WHERE canvas_id = $2 ORDER BY version_id DESC LIMIT 1), $3, $4, $5, $6)
+		`, utils.GenerateID(), canvasID, edit.UserID, edit.PixelCount, edit.Data, edit.Timestamp)
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to insert edits: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ScanEdits calls fn with the most recent edit batches of the canvas, newest first, until fn returns false.
+// At most limit batches are read, older batches are skipped.
+func (q *Queries) ScanEdits(canvasID string, limit int, fn func(edit types.Edit) bool) error {
+	query := `
+		SELECT edit_id, canvas_id, version_id, user_id, pixel_count, data, timestamp FROM edits
+		WHERE canvas_id = $1 ORDER BY edit_id DESC LIMIT $2
+	`
+
+	rows, err := q.pool.Query(context.Background(), query, canvasID, limit)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var edit types.Edit
+		err = rows.Scan(
+			&edit.ID,
+			&edit.CanvasID,
+			&edit.VersionID,
+			&edit.UserID,
+			&edit.PixelCount,
+			&edit.Data,
+			&edit.Timestamp,
+		)
+		if err != nil {
+			return err
+		}
+
+		if !fn(edit) {
+			return nil
+		}
+	}
+
+	return rows.Err()
+}
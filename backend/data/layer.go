@@ -0,0 +1,219 @@
+package data
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+func (q *Queries) GetLayers(canvasID string) ([]types.Layer, error) {
+	query := `SELECT layer_id, canvas_id, name, position, visible, opacity, locked, created_at FROM layers WHERE canvas_id = $1 ORDER BY position`
+
+	var layers []types.Layer
+	rows, err := q.pool.Query(context.Background(), query, canvasID)
+	if err != nil {
+		return layers, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var layer types.Layer
+		err = rows.Scan(
+			&layer.ID,
+			&layer.CanvasID,
+			&layer.Name,
+			&layer.Position,
+			&layer.Visible,
+			&layer.Opacity,
+			&layer.Locked,
+			&layer.CreatedAt,
+		)
+		if err != nil {
+			return layers, err
+		}
+
+		layers = append(layers, layer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return layers, nil
+}
+
+// CreateLayer inserts a new layer on top of the existing layers of the canvas along with its initial tiles.
+func (q *Queries) CreateLayer(canvasID, name string, tiles []types.Tile) (types.Layer, error) {
+	ctx := context.Background()
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return types.Layer{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO layers (layer_id, canvas_id, name, position)
+		VALUES ($1, $2, $3, (SELECT coalesce(max(position) + 1, 0) FROM layers WHERE canvas_id = $2))
+		RETURNING position, visible, opacity, locked, created_at
+	`
+
+	layer := types.Layer{
+		ID:       utils.GenerateID(),
+		CanvasID: canvasID,
+		Name:     name,
+	}
+	err = tx.QueryRow(ctx, query, layer.ID, canvasID, name).Scan(
+		&layer.Position,
+		&layer.Visible,
+		&layer.Opacity,
+		&layer.Locked,
+		&layer.CreatedAt,
+	)
+	if err != nil {
+		return layer, err
+	}
+
+	if len(tiles) > 0 {
+		batch := &pgx.Batch{}
+		for _, tile := range tiles {
+			batch.Queue(`INSERT INTO layer_tiles (layer_id, tile_x, tile_y, data) VALUES ($1, $2, $3, $4)`, layer.ID, tile.X, tile.Y, tile.PixelData)
+		}
+
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return layer, fmt.Errorf("failed to insert layer tiles: %w", err)
+		}
+	}
+
+	return layer, tx.Commit(ctx)
+}
+
+// CreateDefaultLayer creates the first layer of a canvas from its initial pixels, which are also the flattened canvas.
+// The canvas row is locked so that concurrent calls create a single layer, nothing is created if the canvas already has layers.
+func (q *Queries) CreateDefaultLayer(canvasID, name string, tiles []types.Tile) error {
+	ctx := context.Background()
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM canvases WHERE canvas_id = $1 FOR UPDATE`, canvasID); err != nil {
+		return err
+	}
+
+	var exists bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM layers WHERE canvas_id = $1)`, canvasID).Scan(&exists)
+	if err != nil || exists {
+		return err
+	}
+
+	layerID := utils.GenerateID()
+	batch := &pgx.Batch{}
+	batch.Queue(`INSERT INTO layers (layer_id, canvas_id, name, position) VALUES ($1, $2, $3, 0)`, layerID, canvasID, name)
+	for _, tile := range tiles {
+		batch.Queue(`INSERT INTO layer_tiles (layer_id, tile_x, tile_y, data) VALUES ($1, $2, $3, $4)`, layerID, tile.X, tile.Y, tile.PixelData)
+		batch.Queue(`
+			INSERT INTO canvas_tiles (canvas_id, tile_x, tile_y, data) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (canvas_id, tile_x, tile_y) DO UPDATE SET data = excluded.data
+		`, canvasID, tile.X, tile.Y, tile.PixelData)
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to insert default layer: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (q *Queries) UpdateLayer(canvasID, layerID, name string, visible bool, opacity uint8, locked bool) (types.Layer, error) {
+	query := `
+		UPDATE layers SET name = $1, visible = $2, opacity = $3, locked = $4
+		WHERE canvas_id = $5 AND layer_id = $6
+		RETURNING layer_id, canvas_id, name, position, visible, opacity, locked, created_at
+	`
+
+	var layer types.Layer
+	err := q.pool.QueryRow(context.Background(), query, name, visible, opacity, locked, canvasID, layerID).Scan(
+		&layer.ID,
+		&layer.CanvasID,
+		&layer.Name,
+		&layer.Position,
+		&layer.Visible,
+		&layer.Opacity,
+		&layer.Locked,
+		&layer.CreatedAt,
+	)
+	return layer, err
+}
+
+func (q *Queries) DeleteLayer(canvasID, layerID string) error {
+	query := `DELETE FROM layers WHERE canvas_id = $1 AND layer_id = $2`
+
+	tag, err := q.pool.Exec(context.Background(), query, canvasID, layerID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return types.ErrLayerNotFound
+	}
+
+	return nil
+}
+
+// ReorderLayers sets the position of each layer to its index in layerIDs.
+func (q *Queries) ReorderLayers(canvasID string, layerIDs []string) error {
+	ctx := context.Background()
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for position, layerID := range layerIDs {
+		batch.Queue(`UPDATE layers SET position = $1 WHERE canvas_id = $2 AND layer_id = $3`, position, canvasID, layerID)
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to update layer positions: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ReplaceLayers replaces every layer of the canvas and the tiles of the flattened canvas, used to restore a version.
+func (q *Queries) ReplaceLayers(canvasID string, layers []types.Layer, layerTiles, canvasTiles []types.Tile) error {
+	ctx := context.Background()
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	batch.Queue(`DELETE FROM layers WHERE canvas_id = $1`, canvasID)
+	batch.Queue(`DELETE FROM canvas_tiles WHERE canvas_id = $1`, canvasID)
+	for _, layer := range layers {
+		batch.Queue(`
+			INSERT INTO layers (layer_id, canvas_id, name, position, visible, opacity, locked)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, layer.ID, canvasID, layer.Name, layer.Position, layer.Visible, layer.Opacity, layer.Locked)
+	}
+	for _, tile := range layerTiles {
+		batch.Queue(`INSERT INTO layer_tiles (layer_id, tile_x, tile_y, data) VALUES ($1, $2, $3, $4)`, tile.LayerID, tile.X, tile.Y, tile.PixelData)
+	}
+	for _, tile := range canvasTiles {
+		batch.Queue(`INSERT INTO canvas_tiles (canvas_id, tile_x, tile_y, data) VALUES ($1, $2, $3, $4)`, canvasID, tile.X, tile.Y, tile.PixelData)
+	}
+	batch.Queue(`UPDATE canvases SET last_edited_at = now() WHERE canvas_id = $1`, canvasID)
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to replace layers: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,90 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateCanvasOps appends the operations to the operation log of the canvas, in order.
+func (q *Queries) CreateCanvasOps(canvasID string, ops []types.CanvasOp) error {
+	ctx := context.Background()
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, op := range ops {
+		batch.Queue(`INSERT INTO canvas_ops (canvas_id, keyframe, data, timestamp) VALUES ($1, $2, $3, $4)`, canvasID, op.Keyframe, op.Data, op.Timestamp)
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to insert canvas operations: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// HasCanvasOps reports whether the operation log of the canvas has any operation.
+func (q *Queries) HasCanvasOps(canvasID string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM canvas_ops WHERE canvas_id = $1)`
+
+	var exists bool
+	err := q.pool.QueryRow(context.Background(), query, canvasID).Scan(&exists)
+	return exists, err
+}
+
+// ScanCanvasOps calls fn with the operations of the canvas up to the given time in order, until fn returns false.
+// When fromKeyframe is set the scan starts at the last keyframe before that time instead of at the first operation.
+func (q *Queries) ScanCanvasOps(canvasID string, until time.Time, fromKeyframe bool, fn func(op types.CanvasOp) bool) error {
+	query := `SELECT seq, canvas_id, keyframe, data, timestamp FROM canvas_ops WHERE canvas_id = $1 AND timestamp <= $2 ORDER BY seq`
+	if fromKeyframe {
+		query = `
+			SELECT seq, canvas_id, keyframe, data, timestamp FROM canvas_ops
+			WHERE canvas_id = $1 AND timestamp <= $2 AND seq >= coalesce(
+				(SELECT max(seq) FROM canvas_ops WHERE canvas_id = $1 AND keyframe AND timestamp <= $2), 0
+			)
+			ORDER BY seq
+		`
+	}
+
+	rows, err := q.pool.Query(context.Background(), query, canvasID, until)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var op types.CanvasOp
+		if err := rows.Scan(&op.Seq, &op.CanvasID, &op.Keyframe, &op.Data, &op.Timestamp); err != nil {
+			return err
+		}
+
+		if !fn(op) {
+			return nil
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetCanvasOpsSpan returns the times of the first and last operation of the canvas, both zero if it has none.
+func (q *Queries) GetCanvasOpsSpan(canvasID string) (time.Time, time.Time, error) {
+	query := `SELECT min(timestamp), max(timestamp) FROM canvas_ops WHERE canvas_id = $1`
+
+	var first, last *time.Time
+	if err := q.pool.QueryRow(context.Background(), query, canvasID).Scan(&first, &last); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if first == nil || last == nil {
+		return time.Time{}, time.Time{}, nil
+	}
+
+	return *first, *last, nil
+}
@@ -0,0 +1,42 @@
+package data
+
+import (
+	"context"
+
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// EditPalette applies the edit to the current palette of the canvas and stores the result.
+// The canvas row is locked while the edit runs so that concurrent edits are not lost.
+func (q *Queries) EditPalette(canvasID string, edit func(palette types.Palette) (types.Palette, error)) (types.Palette, error) {
+	ctx := context.Background()
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var palette types.Palette
+	err = tx.QueryRow(ctx, `SELECT palette FROM canvases WHERE canvas_id = $1 FOR UPDATE`, canvasID).Scan(&palette)
+	if err != nil {
+		return nil, err
+	}
+
+	palette, err = edit(palette)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE canvases SET palette = $1 WHERE canvas_id = $2`, palette, canvasID); err != nil {
+		return nil, err
+	}
+
+	return palette, tx.Commit(ctx)
+}
+
+func (q *Queries) UpdatePaletteLocked(canvasID string, locked bool) error {
+	query := `UPDATE canvases SET palette_locked = $1 WHERE canvas_id = $2`
+
+	_, err := q.pool.Exec(context.Background(), query, locked, canvasID)
+	return err
+}
@@ -0,0 +1,120 @@
+package data
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetLayerTiles returns the stored tiles of every layer of the canvas.
+func (q *Queries) GetLayerTiles(canvasID string) ([]types.Tile, error) {
+	query := `
+		SELECT t.layer_id, t.tile_x, t.tile_y, t.data FROM layer_tiles t
+		JOIN layers l ON l.layer_id = t.layer_id
+		WHERE l.canvas_id = $1
+	`
+
+	var tiles []types.Tile
+	rows, err := q.pool.Query(context.Background(), query, canvasID)
+	if err != nil {
+		return tiles, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tile types.Tile
+		if err := rows.Scan(&tile.LayerID, &tile.X, &tile.Y, &tile.PixelData); err != nil {
+			return tiles, err
+		}
+
+		tiles = append(tiles, tile)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tiles, nil
+}
+
+// GetCanvasTile returns the flattened tile of the canvas, pgx.ErrNoRows is returned when the tile is fully transparent.
+func (q *Queries) GetCanvasTile(canvasID string, x, y int) (types.Tile, error) {
+	query := `SELECT data FROM canvas_tiles WHERE canvas_id = $1 AND tile_x = $2 AND tile_y = $3`
+
+	tile := types.Tile{X: x, Y: y}
+	err := q.pool.QueryRow(context.Background(), query, canvasID, x, y).Scan(&tile.PixelData)
+	return tile, err
+}
+
+// GetCanvasTiles returns every stored tile of the flattened canvas, fully transparent tiles are not stored.
+func (q *Queries) GetCanvasTiles(canvasID string) ([]types.Tile, error) {
+	query := `SELECT tile_x, tile_y, data FROM canvas_tiles WHERE canvas_id = $1`
+
+	var tiles []types.Tile
+	rows, err := q.pool.Query(context.Background(), query, canvasID)
+	if err != nil {
+		return tiles, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tile types.Tile
+		if err := rows.Scan(&tile.X, &tile.Y, &tile.PixelData); err != nil {
+			return tiles, err
+		}
+
+		tiles = append(tiles, tile)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tiles, nil
+}
+
+// SaveTiles writes the given layer and flattened canvas tiles. Tiles without pixel data are deleted.
+func (q *Queries) SaveTiles(canvasID string, layerTiles, canvasTiles []types.Tile) error {
+	ctx := context.Background()
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, tile := range layerTiles {
+		if tile.PixelData == nil {
+			batch.Queue(`DELETE FROM layer_tiles WHERE layer_id = $1 AND tile_x = $2 AND tile_y = $3`, tile.LayerID, tile.X, tile.Y)
+			continue
+		}
+
+		// The layer might have been deleted since the tile was changed
+		batch.Queue(`
+			INSERT INTO layer_tiles (layer_id, tile_x, tile_y, data)
+			SELECT $1, $2, $3, $4 WHERE EXISTS (SELECT 1 FROM layers WHERE layer_id = $1)
+			ON CONFLICT (layer_id, tile_x, tile_y) DO UPDATE SET data = excluded.data
+		`, tile.LayerID, tile.X, tile.Y, tile.PixelData)
+	}
+
+	for _, tile := range canvasTiles {
+		if tile.PixelData == nil {
+			batch.Queue(`DELETE FROM canvas_tiles WHERE canvas_id = $1 AND tile_x = $2 AND tile_y = $3`, canvasID, tile.X, tile.Y)
+			continue
+		}
+
+		batch.Queue(`
+			INSERT INTO canvas_tiles (canvas_id, tile_x, tile_y, data) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (canvas_id, tile_x, tile_y) DO UPDATE SET data = excluded.data
+		`, canvasID, tile.X, tile.Y, tile.PixelData)
+	}
+	batch.Queue(`UPDATE canvases SET last_edited_at = now() WHERE canvas_id = $1`, canvasID)
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to save tiles: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
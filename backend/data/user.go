@@ -29,6 +29,14 @@ func (q *Queries) CreateUser(username, email, password string) (types.User, erro
 	return newUser, nil
 }
 
+func (q *Queries) GetUserByID(userID string) (types.User, error) {
+	query := `SELECT user_id, username, email, created_at, avatar_url FROM users WHERE user_id = $1`
+
+	var user types.User
+	err := q.pool.QueryRow(context.Background(), query, userID).Scan(&user.ID, &user.Username, &user.Email, &user.CreatedAt, &user.AvatarURL)
+	return user, err
+}
+
 func (q *Queries) GetUserByEmail(email string) (types.User, error) {
 	query := `SELECT user_id, username, email, hashed_password, created_at, avatar_url FROM users WHERE email = $1`
 
@@ -36,3 +44,30 @@ func (q *Queries) GetUserByEmail(email string) (types.User, error) {
 	err := q.pool.QueryRow(context.Background(), query, email).Scan(&user.ID, &user.Username, &user.Email, &user.HashedPassword, &user.CreatedAt, &user.AvatarURL)
 	return user, err
 }
+
+// GetUsersByIDs returns the users with the given IDs, in no particular order.
+func (q *Queries) GetUsersByIDs(userIDs []string) ([]types.User, error) {
+	query := `SELECT user_id, username, email, created_at, avatar_url FROM users WHERE user_id = ANY($1)`
+
+	users := []types.User{}
+	rows, err := q.pool.Query(context.Background(), query, userIDs)
+	if err != nil {
+		return users, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user types.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.CreatedAt, &user.AvatarURL); err != nil {
+			return users, err
+		}
+
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
@@ -0,0 +1,144 @@
+package data
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateVersion stores the version along with the pixel data of its layers.
+func (q *Queries) CreateVersion(canvasID, label, createdBy string, editCount int, pixelData []byte, layers []types.VersionLayer) (types.Version, error) {
+	ctx := context.Background()
+	tx, err := q.pool.Begin(ctx)
+	if err != nil {
+		return types.Version{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO versions (version_id, canvas_id, data, edit_count, label, created_by)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''))
+		RETURNING timestamp
+	`
+
+	version := types.Version{
+		ID:        utils.GenerateID(),
+		CanvasID:  canvasID,
+		Label:     types.NullString(label),
+		CreatedBy: types.NullString(createdBy),
+		EditCount: editCount,
+	}
+	err = tx.QueryRow(ctx, query, version.ID, canvasID, pixelData, editCount, label, createdBy).Scan(&version.Timestamp)
+	if err != nil {
+		return version, err
+	}
+
+	batch := &pgx.Batch{}
+	for _, layer := range layers {
+		batch.Queue(`
+			INSERT INTO version_layers (version_id, layer_id, name, position, visible, opacity, locked, data)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, version.ID, layer.ID, layer.Name, layer.Position, layer.Visible, layer.Opacity, layer.Locked, layer.PixelData)
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return version, fmt.Errorf("failed to insert version layers: %w", err)
+	}
+
+	return version, tx.Commit(ctx)
+}
+
+// GetVersions returns the versions of the canvas without their pixel data, newest first.
+func (q *Queries) GetVersions(canvasID string) ([]types.Version, error) {
+	query := `
+		SELECT version_id, canvas_id, label, created_by, edit_count, timestamp
+		FROM versions WHERE canvas_id = $1 ORDER BY version_id DESC
+	`
+
+	versions := []types.Version{}
+	rows, err := q.pool.Query(context.Background(), query, canvasID)
+	if err != nil {
+		return versions, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version types.Version
+		err = rows.Scan(
+			&version.ID,
+			&version.CanvasID,
+			&version.Label,
+			&version.CreatedBy,
+			&version.EditCount,
+			&version.Timestamp,
+		)
+		if err != nil {
+			return versions, err
+		}
+
+		versions = append(versions, version)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// GetVersion returns the version with the pixel data of the flattened image and of every layer.
+func (q *Queries) GetVersion(canvasID, versionID string) (types.Version, error) {
+	ctx := context.Background()
+	query := `
+		SELECT version_id, canvas_id, label, created_by, edit_count, timestamp, data
+		FROM versions WHERE canvas_id = $1 AND version_id = $2
+	`
+
+	var version types.Version
+	err := q.pool.QueryRow(ctx, query, canvasID, versionID).Scan(
+		&version.ID,
+		&version.CanvasID,
+		&version.Label,
+		&version.CreatedBy,
+		&version.EditCount,
+		&version.Timestamp,
+		&version.PixelData,
+	)
+	if err != nil {
+		return version, err
+	}
+
+	query = `
+		SELECT layer_id, name, position, visible, opacity, locked, data
+		FROM version_layers WHERE version_id = $1 ORDER BY position
+	`
+
+	rows, err := q.pool.Query(ctx, query, versionID)
+	if err != nil {
+		return version, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var layer types.VersionLayer
+		err = rows.Scan(
+			&layer.ID,
+			&layer.Name,
+			&layer.Position,
+			&layer.Visible,
+			&layer.Opacity,
+			&layer.Locked,
+			&layer.PixelData,
+		)
+		if err != nil {
+			return version, err
+		}
+
+		version.Layers = append(version.Layers, layer)
+	}
+
+	return version, rows.Err()
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/CDavidSV/Pixio/types"
 	"github.com/CDavidSV/Pixio/utils"
+	"github.com/CDavidSV/Pixio/websocket"
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5"
 )
@@ -78,6 +79,9 @@ func (h *Handler) PostDeleteAccess(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The user might still be connected to the canvas
+	h.websocket.RefreshUserAccess(canvasID, deleteAccessDTO.UserID, websocket.ReasonAccessRemoved)
+
 	utils.WriteJSON(w, http.StatusOK, types.Map{
 		"message": "Access removed from user",
 		"user_id": deleteAccessDTO.UserID,
@@ -110,6 +114,8 @@ func (h *Handler) PutUpdateAccess(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.websocket.RefreshUserAccess(canvasID, updateAccessDTO.UserID, websocket.ReasonRoleChanged)
+
 	utils.WriteJSON(w, http.StatusOK, types.Map{
 		"message":     "Global canvas access rules updated",
 		"access_role": updateAccessDTO.AccessRole,
@@ -157,6 +163,9 @@ func (h *Handler) PutUpdateGlobalAccess(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Users connected through the link might have lost or gained access
+	h.websocket.RefreshRoomAccess(canvasID, websocket.ReasonLinkAccessChanged)
+
 	utils.WriteJSON(w, http.StatusOK, types.Map{
 		"message":     "Global canvas access rules updated",
 		"access_type": updateGlobalAccessDTO.LinkAccessType,
@@ -48,6 +48,22 @@ func (h *Handler) GetCanvas(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The stored pixel data is only the initial canvas, the current pixels are composed from the tiles
+	pixelData, ok := h.websocket.FlattenedCanvas(canvasID)
+	if !ok {
+		pixelData, err = h.services.CanvasService.LoadFlattenedCanvas(canvasID, canvas.Width, canvas.Height)
+		if err != nil {
+			utils.ServerError(w, r, err, "Failed to load canvas")
+			return
+		}
+	}
+
+	canvas.PixelData, err = h.services.CanvasService.CompressPixelData(pixelData)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to encode canvas")
+		return
+	}
+
 	utils.WriteJSON(w, http.StatusOK, types.Map{
 		"canvas": canvas,
 		"access": userAccess,
@@ -76,6 +92,9 @@ func (h *Handler) DeleteCanvas(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Drop the live room so that its unsaved changes are not written to the deleted canvas
+	h.websocket.CloseRoom(canvasID)
+
 	utils.WriteJSON(w, http.StatusOK, types.Map{
 		"message": "Canvas deleted successfully",
 	})
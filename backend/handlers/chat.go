@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/CDavidSV/Pixio/config"
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetChatMessages returns a page of the canvas chat history, newest first.
+// The next page is requested by passing the returned next_cursor as the before query parameter.
+func (h *Handler) GetChatMessages(w http.ResponseWriter, r *http.Request) {
+	canvasID := chi.URLParam(r, "id")
+	before := r.URL.Query().Get("before")
+
+	if before != "" && len(before) != 26 {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidID)
+		return
+	}
+
+	limit := config.ChatHistoryPageSize
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidPageLimit)
+			return
+		}
+
+		limit = min(parsed, config.ChatHistoryPageSize)
+	}
+
+	messages, err := h.queries.GetChatMessages(canvasID, before, limit)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch chat messages")
+		return
+	}
+
+	// A full page means there may be older messages
+	var nextCursor *string
+	if len(messages) == limit {
+		nextCursor = &messages[len(messages)-1].ID
+	}
+
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"messages":    messages,
+		"next_cursor": nextCursor,
+	})
+}
+
+func (h *Handler) PutChatSettings(w http.ResponseWriter, r *http.Request) {
+	userAccess := r.Context().Value(utils.AccessRuleKey).(types.UserAccess)
+	canvasID := chi.URLParam(r, "id")
+
+	if userAccess.AccessRole != types.Owner {
+		utils.ClientError(w, http.StatusUnauthorized, utils.ErrNotCanvasOwner)
+		return
+	}
+
+	updateChatSettingsDTO, ok := utils.DecodeJSONAndValidate[types.UpdateChatSettingsDTO](w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.queries.UpdateViewerChat(canvasID, updateChatSettingsDTO.ViewerChat); err != nil {
+		utils.ServerError(w, r, err, "Failed to update chat settings")
+		return
+	}
+
+	h.websocket.SetViewerChat(canvasID, updateChatSettingsDTO.ViewerChat)
+
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"message":     "Chat settings updated",
+		"viewer_chat": updateChatSettingsDTO.ViewerChat,
+	})
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+func (h *Handler) GetComments(w http.ResponseWriter, r *http.Request) {
+	canvasID := chi.URLParam(r, "id")
+
+	comments, err := h.queries.GetComments(canvasID)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch comments")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, comments)
+}
+
+func (h *Handler) GetCommentThread(w http.ResponseWriter, r *http.Request) {
+	canvasID := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentID")
+
+	if len(commentID) != 26 {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidID)
+		return
+	}
+
+	thread, err := h.queries.GetCommentThread(canvasID, commentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.ClientError(w, http.StatusNotFound, utils.ErrCommentNotFound)
+			return
+		}
+
+		utils.ServerError(w, r, err, "Failed to fetch comment")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, thread)
+}
+
+// PostCreateComment starts a comment thread. Anyone with access to the canvas can comment, including viewers.
+func (h *Handler) PostCreateComment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(utils.UserIDKey).(string)
+	canvasID := chi.URLParam(r, "id")
+
+	createCommentDTO, ok := utils.DecodeJSONAndValidate[types.CreateCommentDTO](w, r)
+	if !ok {
+		return
+	}
+
+	canvas, err := h.queries.GetCanvas(canvasID)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch canvas")
+		return
+	}
+
+	// A width and height of 0 anchor the comment to the pixel at (x, y)
+	right, bottom := createCommentDTO.X+max(createCommentDTO.Width, 1), createCommentDTO.Y+max(createCommentDTO.Height, 1)
+	if right > int(canvas.Width) || bottom > int(canvas.Height) {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidAnchor)
+		return
+	}
+
+	comment, err := h.queries.CreateComment(canvasID, userID, createCommentDTO.Content, createCommentDTO.X, createCommentDTO.Y, createCommentDTO.Width, createCommentDTO.Height)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to create comment")
+		return
+	}
+
+	h.websocket.CommentCreated(comment)
+
+	utils.WriteJSON(w, http.StatusOK, comment)
+}
+
+func (h *Handler) PostCreateReply(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(utils.UserIDKey).(string)
+	canvasID := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentID")
+
+	if len(commentID) != 26 {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidID)
+		return
+	}
+
+	replyDTO, ok := utils.DecodeJSONAndValidate[types.CommentContentDTO](w, r)
+	if !ok {
+		return
+	}
+
+	reply, err := h.queries.CreateReply(canvasID, commentID, userID, replyDTO.Content)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.ClientError(w, http.StatusNotFound, utils.ErrCommentNotFound)
+			return
+		}
+
+		utils.ServerError(w, r, err, "Failed to create reply")
+		return
+	}
+
+	h.websocket.CommentCreated(reply)
+
+	utils.WriteJSON(w, http.StatusOK, reply)
+}
+
+// PutUpdateComment edits the content of a comment or reply, only its author can edit it.
+func (h *Handler) PutUpdateComment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(utils.UserIDKey).(string)
+	canvasID := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentID")
+
+	comment, ok := h.getComment(w, r, canvasID, commentID)
+	if !ok {
+		return
+	}
+
+	if comment.UserID != userID {
+		utils.ClientError(w, http.StatusUnauthorized, utils.ErrCommentEditForbidden)
+		return
+	}
+
+	updateCommentDTO, ok := utils.DecodeJSONAndValidate[types.CommentContentDTO](w, r)
+	if !ok {
+		return
+	}
+
+	comment, err := h.queries.UpdateCommentContent(canvasID, commentID, updateCommentDTO.Content)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.ClientError(w, http.StatusNotFound, utils.ErrCommentNotFound)
+			return
+		}
+
+		utils.ServerError(w, r, err, "Failed to update comment")
+		return
+	}
+
+	h.websocket.CommentUpdated(comment)
+
+	utils.WriteJSON(w, http.StatusOK, comment)
+}
+
+// PutResolveComment resolves or reopens a comment thread, either its author or an editor of the canvas can do so.
+func (h *Handler) PutResolveComment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(utils.UserIDKey).(string)
+	userAccess := r.Context().Value(utils.AccessRuleKey).(types.UserAccess)
+	canvasID := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentID")
+
+	comment, ok := h.getComment(w, r, canvasID, commentID)
+	if !ok {
+		return
+	}
+
+	if comment.UserID != userID && userAccess.AccessRole == types.Viewer {
+		utils.ClientError(w, http.StatusUnauthorized, utils.ErrCommentEditForbidden)
+		return
+	}
+
+	resolveCommentDTO, ok := utils.DecodeJSONAndValidate[types.ResolveCommentDTO](w, r)
+	if !ok {
+		return
+	}
+
+	// Replies are resolved together with their thread
+	comment, err := h.queries.ResolveComment(canvasID, commentID, userID, resolveCommentDTO.Resolved)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.ClientError(w, http.StatusNotFound, utils.ErrCommentNotFound)
+			return
+		}
+
+		utils.ServerError(w, r, err, "Failed to resolve comment")
+		return
+	}
+
+	h.websocket.CommentUpdated(comment)
+
+	utils.WriteJSON(w, http.StatusOK, comment)
+}
+
+// DeleteComment deletes a comment or reply, either its author or the owner of the canvas can do so.
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(utils.UserIDKey).(string)
+	userAccess := r.Context().Value(utils.AccessRuleKey).(types.UserAccess)
+	canvasID := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentID")
+
+	comment, ok := h.getComment(w, r, canvasID, commentID)
+	if !ok {
+		return
+	}
+
+	if comment.UserID != userID && userAccess.AccessRole != types.Owner {
+		utils.ClientError(w, http.StatusUnauthorized, utils.ErrCommentEditForbidden)
+		return
+	}
+
+	if err := h.queries.DeleteComment(canvasID, commentID); err != nil {
+		if errors.Is(err, types.ErrCommentNotFound) {
+			utils.ClientError(w, http.StatusNotFound, utils.ErrCommentNotFound)
+			return
+		}
+
+		utils.ServerError(w, r, err, "Failed to delete comment")
+		return
+	}
+
+	h.websocket.CommentDeleted(comment)
+
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"message":    "Comment deleted successfully",
+		"comment_id": commentID,
+	})
+}
+
+// getComment fetches the comment for a permission check, writing the error response if it cannot be fetched.
+func (h *Handler) getComment(w http.ResponseWriter, r *http.Request, canvasID, commentID string) (types.Comment, bool) {
+	if len(commentID) != 26 {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidID)
+		return types.Comment{}, false
+	}
+
+	comment, err := h.queries.GetComment(canvasID, commentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.ClientError(w, http.StatusNotFound, utils.ErrCommentNotFound)
+			return comment, false
+		}
+
+		utils.ServerError(w, r, err, "Failed to fetch comment")
+		return comment, false
+	}
+
+	return comment, true
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetBlame returns the user and time of the most recent change of the pixel at (x, y), or of any pixel
+// in the area when a width and height are given. last_edit is null when none of the pixels were changed.
+func (h *Handler) GetBlame(w http.ResponseWriter, r *http.Request) {
+	canvasID := chi.URLParam(r, "id")
+
+	canvas, err := h.queries.GetCanvas(canvasID)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch canvas")
+		return
+	}
+
+	x, y, width, height, ok := parseArea(r, canvas.Width, canvas.Height)
+	if !ok {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidArea)
+		return
+	}
+
+	blame, err := h.services.CanvasService.Blame(canvasID, canvas.Width, x, y, width, height, h.websocket.PendingEdits(canvasID))
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch blame")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"x":         x,
+		"y":         y,
+		"width":     width,
+		"height":    height,
+		"last_edit": blame,
+	})
+}
+
+func (h *Handler) GetContributions(w http.ResponseWriter, r *http.Request) {
+	canvasID := chi.URLParam(r, "id")
+
+	canvas, err := h.queries.GetCanvas(canvasID)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch canvas")
+		return
+	}
+
+	contributions, err := h.services.CanvasService.Contributions(canvasID, canvas.Width, canvas.Height, h.websocket.PendingEdits(canvasID))
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch contributions")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, contributions)
+}
+
+// parseArea reads the x, y, width and height query parameters, the width and height default to a single pixel.
+// Returns false if a parameter is not a number or the area is not inside the canvas.
+func parseArea(r *http.Request, canvasWidth, canvasHeight uint16) (x, y, width, height int, ok bool) {
+	values := []*int{&x, &y, &width, &height}
+	width, height = 1, 1
+
+	for i, name := range []string{"x", "y", "width", "height"} {
+		param := r.URL.Query().Get(name)
+		if param == "" {
+			// x and y are required
+			if i < 2 {
+				return 0, 0, 0, 0, false
+			}
+			continue
+		}
+
+		value, err := strconv.Atoi(param)
+		if err != nil {
+			return 0, 0, 0, 0, false
+		}
+		*values[i] = value
+	}
+
+	if x < 0 || y < 0 || width <= 0 || height <= 0 || x+width > int(canvasWidth) || y+height > int(canvasHeight) {
+		return 0, 0, 0, 0, false
+	}
+
+	return x, y, width, height, true
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"image/png"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/CDavidSV/Pixio/config"
+	"github.com/CDavidSV/Pixio/services"
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetExportPNG renders the flattened canvas as a PNG image.
+// Accepts an integer scale, a background color drawn behind transparent pixels and a grid color for lines between the canvas pixels.
+func (h *Handler) GetExportPNG(w http.ResponseWriter, r *http.Request) {
+	canvasID := chi.URLParam(r, "id")
+	query := r.URL.Query()
+
+	canvas, err := h.queries.GetCanvas(canvasID)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch canvas")
+		return
+	}
+
+	opts := services.ExportOptions{Scale: 1}
+	if param := query.Get("scale"); param != "" {
+		opts.Scale, err = strconv.Atoi(param)
+		if err != nil || opts.Scale < 1 || opts.Scale > config.ExportMaxScale ||
+			int(canvas.Width)*opts.Scale > config.ExportMaxSize || int(canvas.Height)*opts.Scale > config.ExportMaxSize {
+			utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidImageScale)
+			return
+		}
+	}
+
+	var backgroundOK, gridOK bool
+	opts.Background, backgroundOK = parseColorParam(r, "background")
+	opts.Grid, gridOK = parseColorParam(r, "grid")
+	if !backgroundOK || !gridOK {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidColor)
+		return
+	}
+
+	if opts.Grid != nil && opts.Scale < 2 {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrGridScale)
+		return
+	}
+
+	// Live canvases have changes that are not saved yet
+	pixelData, ok := h.websocket.FlattenedCanvas(canvasID)
+	if !ok {
+		pixelData, err = h.services.CanvasService.LoadFlattenedCanvas(canvasID, canvas.Width, canvas.Height)
+		if err != nil {
+			utils.ServerError(w, r, err, "Failed to load canvas")
+			return
+		}
+	}
+
+	img := h.services.CanvasService.RenderImage(pixelData, canvas.Width, canvas.Height, opts)
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.png"`, canvasID))
+	w.WriteHeader(http.StatusOK)
+	if err := png.Encode(w, img); err != nil {
+		slog.Error("Failed to write exported image", "canvasID", canvasID, "error", err)
+	}
+}
+
+// parseColorParam parses the color in the query parameter, returning nil if the parameter is not set.
+// Returns false if the parameter is not a valid color.
+func parseColorParam(r *http.Request, name string) (*types.Pixel, bool) {
+	param := r.URL.Query().Get(name)
+	if param == "" {
+		return nil, true
+	}
+
+	color, err := types.ParseColor(param)
+	if err != nil {
+		return nil, false
+	}
+
+	return &color, true
+}
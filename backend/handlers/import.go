@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/CDavidSV/Pixio/config"
+	"github.com/CDavidSV/Pixio/services"
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/CDavidSV/Pixio/validator"
+	"github.com/go-chi/chi/v5"
+)
+
+// importedImage is an uploaded image after it was resized and quantized.
+type importedImage struct {
+	pixels []types.Pixel
+	width  int
+	height int
+}
+
+// PostImportCanvas creates a canvas from the uploaded image.
+// The form holds the title and description of the canvas along with the import options read by readImportedImage.
+func (h *Handler) PostImportCanvas(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(utils.UserIDKey).(string)
+
+	var createCanvasDTO types.CreateCanvasDTO
+	img, ok := h.readImportedImage(w, r, func(width, height int) bool {
+		// Larger sizes are clamped so that they still fail the validation instead of overflowing
+		createCanvasDTO = types.CreateCanvasDTO{
+			Title:       r.FormValue("title"),
+			Description: r.FormValue("description"),
+			Width:       uint16(min(width, math.MaxUint16)),
+			Height:      uint16(min(height, math.MaxUint16)),
+		}
+
+		result, err := validator.Validate(createCanvasDTO)
+		if err != nil {
+			utils.ServerError(w, r, err, "Error validating request body")
+			return false
+		}
+
+		if !result.IsValid {
+			result.SendValidationError(w)
+			return false
+		}
+
+		return true
+	})
+	if !ok {
+		return
+	}
+
+	pixelBytes, err := h.services.CanvasService.CompressPixelData(img.pixels)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to import image")
+		return
+	}
+
+	canvas, err := h.queries.CreateCanvas(createCanvasDTO.Title, createCanvasDTO.Description, userID, createCanvasDTO.Width, createCanvasDTO.Height, pixelBytes)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to create canvas")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"canvas_id":   canvas.ID,
+		"created_at":  canvas.CreatedAt,
+		"access_type": canvas.LinkAccessType,
+		"width":       canvas.Width,
+		"height":      canvas.Height,
+		"pixel_data":  canvas.PixelData,
+	})
+}
+
+// PostImportImage pastes the uploaded image into a layer of the canvas with its top left corner at (x, y).
+// Pixels that fall outside of the canvas are dropped, so the offset may be negative.
+func (h *Handler) PostImportImage(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(utils.UserIDKey).(string)
+	userAccess := r.Context().Value(utils.AccessRuleKey).(types.UserAccess)
+	canvasID := chi.URLParam(r, "id")
+
+	if userAccess.AccessRole == types.Viewer {
+		utils.ClientError(w, http.StatusUnauthorized, utils.ErrCanvasEditForbidden)
+		return
+	}
+
+	canvas, err := h.queries.GetCanvas(canvasID)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch canvas")
+		return
+	}
+
+	img, ok := h.readImportedImage(w, r, func(width, height int) bool {
+		if width > int(canvas.Width) || height > int(canvas.Height) {
+			utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidImport)
+			return false
+		}
+
+		return true
+	})
+	if !ok {
+		return
+	}
+
+	layerID := r.FormValue("layer_id")
+	if len(layerID) != 26 {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidID)
+		return
+	}
+
+	x, errX := formInt(r, "x", 0)
+	y, errY := formInt(r, "y", 0)
+	if errX != nil || errY != nil {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidArea)
+		return
+	}
+
+	if err := h.websocket.ImportImage(canvasID, layerID, userID, x, y, img.width, img.height, img.pixels); err != nil {
+		switch {
+		case errors.Is(err, types.ErrLayerNotFound):
+			utils.ClientError(w, http.StatusNotFound, utils.ErrLayerNotFound)
+		case errors.Is(err, types.ErrLayerLocked):
+			utils.ClientError(w, http.StatusConflict, utils.ErrLayerLocked)
+		default:
+			utils.ServerError(w, r, err, "Failed to import image")
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"message":  "Image imported successfully",
+		"layer_id": layerID,
+		"x":        x,
+		"y":        y,
+		"width":    img.width,
+		"height":   img.height,
+	})
+}
+
+// readImportedImage decodes the image uploaded in the image field of the multipart form and prepares it for import.
+// The image is resized to width and height using the nearest or box filter, a missing dimension keeps the aspect ratio and
+// missing both keeps the image size. colors quantizes the image to at most that many colors.
+// validateSize checks the target size before the image is resized, writing the error response if it is rejected.
+func (h *Handler) readImportedImage(w http.ResponseWriter, r *http.Request, validateSize func(width, height int) bool) (importedImage, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, config.ImportMaxUploadSize)
+	if err := r.ParseMultipartForm(config.ImportMaxUploadSize); err != nil {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidImage)
+		return importedImage{}, false
+	}
+
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidImage)
+		return importedImage{}, false
+	}
+	defer file.Close()
+
+	width, errW := formInt(r, "width", 0)
+	height, errH := formInt(r, "height", 0)
+	colors, errC := formInt(r, "colors", 0)
+	filter := services.ResizeFilter(r.FormValue("filter"))
+	if filter == "" {
+		filter = services.NearestFilter
+	}
+
+	if errW != nil || errH != nil || errC != nil || width < 0 || height < 0 ||
+		(colors != 0 && (colors < 2 || colors > types.MaxPaletteColors)) ||
+		(filter != services.NearestFilter && filter != services.BoxFilter) {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidImport)
+		return importedImage{}, false
+	}
+
+	pixels, srcW, srcH, err := h.services.CanvasService.DecodeImage(file)
+	if err != nil {
+		if errors.Is(err, types.ErrInvalidImage) || errors.Is(err, types.ErrImageTooLarge) {
+			utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidImage)
+			return importedImage{}, false
+		}
+
+		utils.ServerError(w, r, err, "Failed to decode image")
+		return importedImage{}, false
+	}
+
+	switch {
+	case width == 0 && height == 0:
+		width, height = srcW, srcH
+	case width == 0:
+		width = max((srcW*height+srcH/2)/srcH, 1)
+	case height == 0:
+		height = max((srcH*width+srcW/2)/srcW, 1)
+	}
+
+	if !validateSize(width, height) {
+		return importedImage{}, false
+	}
+
+	pixels = h.services.CanvasService.ResizeImage(pixels, srcW, srcH, width, height, filter)
+	if colors != 0 {
+		pixels = h.services.CanvasService.QuantizeColors(pixels, colors)
+	}
+
+	return importedImage{pixels: pixels, width: width, height: height}, true
+}
+
+// formInt parses the integer form value, returning the default value if it is not set.
+func formInt(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.FormValue(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	return strconv.Atoi(value)
+}
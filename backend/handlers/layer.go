@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+func (h *Handler) GetLayers(w http.ResponseWriter, r *http.Request) {
+	canvasID := chi.URLParam(r, "id")
+
+	layers, err := h.services.CanvasService.GetLayers(canvasID)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch layers")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, layers)
+}
+
+func (h *Handler) PostCreateLayer(w http.ResponseWriter, r *http.Request) {
+	userAccess := r.Context().Value(utils.AccessRuleKey).(types.UserAccess)
+	canvasID := chi.URLParam(r, "id")
+
+	if userAccess.AccessRole == types.Viewer {
+		utils.ClientError(w, http.StatusUnauthorized, utils.ErrCanvasEditForbidden)
+		return
+	}
+
+	createLayerDTO, ok := utils.DecodeJSONAndValidate[types.CreateLayerDTO](w, r)
+	if !ok {
+		return
+	}
+
+	layer, err := h.services.CanvasService.CreateLayer(canvasID, createLayerDTO.Name)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to create layer")
+		return
+	}
+
+	h.websocket.AddLayer(canvasID, layer)
+
+	utils.WriteJSON(w, http.StatusOK, layer)
+}
+
+func (h *Handler) PutUpdateLayer(w http.ResponseWriter, r *http.Request) {
+	userAccess := r.Context().Value(utils.AccessRuleKey).(types.UserAccess)
+	canvasID := chi.URLParam(r, "id")
+	layerID := chi.URLParam(r, "layerID")
+
+	if len(layerID) != 26 {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidID)
+		return
+	}
+
+	if userAccess.AccessRole == types.Viewer {
+		utils.ClientError(w, http.StatusUnauthorized, utils.ErrCanvasEditForbidden)
+		return
+	}
+
+	updateLayerDTO, ok := utils.DecodeJSONAndValidate[types.UpdateLayerDTO](w, r)
+	if !ok {
+		return
+	}
+
+	layer, err := h.queries.UpdateLayer(canvasID, layerID, updateLayerDTO.Name, updateLayerDTO.Visible, uint8(updateLayerDTO.Opacity), updateLayerDTO.Locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.ClientError(w, http.StatusNotFound, utils.ErrLayerNotFound)
+			return
+		}
+
+		utils.ServerError(w, r, err, "Failed to update layer")
+		return
+	}
+
+	if !h.websocket.UpdateLayer(canvasID, layer) {
+		h.rebuildCanvasTiles(r, canvasID)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, layer)
+}
+
+func (h *Handler) PutReorderLayers(w http.ResponseWriter, r *http.Request) {
+	userAccess := r.Context().Value(utils.AccessRuleKey).(types.UserAccess)
+	canvasID := chi.URLParam(r, "id")
+
+	if userAccess.AccessRole == types.Viewer {
+		utils.ClientError(w, http.StatusUnauthorized, utils.ErrCanvasEditForbidden)
+		return
+	}
+
+	reorderLayersDTO, ok := utils.DecodeJSONAndValidate[types.ReorderLayersDTO](w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.services.CanvasService.ReorderLayers(canvasID, reorderLayersDTO.LayerIDs); err != nil {
+		if errors.Is(err, types.ErrInvalidLayerOrder) {
+			utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidLayerOrder)
+			return
+		}
+
+		utils.ServerError(w, r, err, "Failed to reorder layers")
+		return
+	}
+
+	if !h.websocket.ReorderLayers(canvasID, reorderLayersDTO.LayerIDs) {
+		h.rebuildCanvasTiles(r, canvasID)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"message":   "Layers reordered successfully",
+		"layer_ids": reorderLayersDTO.LayerIDs,
+	})
+}
+
+func (h *Handler) DeleteLayer(w http.ResponseWriter, r *http.Request) {
+	userAccess := r.Context().Value(utils.AccessRuleKey).(types.UserAccess)
+	canvasID := chi.URLParam(r, "id")
+	layerID := chi.URLParam(r, "layerID")
+
+	if len(layerID) != 26 {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidID)
+		return
+	}
+
+	if userAccess.AccessRole == types.Viewer {
+		utils.ClientError(w, http.StatusUnauthorized, utils.ErrCanvasEditForbidden)
+		return
+	}
+
+	if err := h.services.CanvasService.DeleteLayer(canvasID, layerID); err != nil {
+		switch {
+		case errors.Is(err, types.ErrLayerNotFound):
+			utils.ClientError(w, http.StatusNotFound, utils.ErrLayerNotFound)
+		case errors.Is(err, types.ErrLastLayer):
+			utils.ClientError(w, http.StatusConflict, utils.ErrLastLayer)
+		default:
+			utils.ServerError(w, r, err, "Failed to delete layer")
+		}
+		return
+	}
+
+	if !h.websocket.RemoveLayer(canvasID, layerID) {
+		h.rebuildCanvasTiles(r, canvasID)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"message":  "Layer deleted successfully",
+		"layer_id": layerID,
+	})
+}
+
+// rebuildCanvasTiles recomposes the stored flattened canvas after a layer change that no live room will save.
+// The layer change itself already succeeded, so failures are only logged.
+func (h *Handler) rebuildCanvasTiles(r *http.Request, canvasID string) {
+	if err := h.services.CanvasService.RebuildCanvasTiles(canvasID); err != nil {
+		slog.Error("Failed to rebuild canvas tiles", "canvasID", canvasID, "path", r.URL.Path, "error", err)
+	}
+}
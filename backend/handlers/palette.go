@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/CDavidSV/Pixio/services"
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) GetPalette(w http.ResponseWriter, r *http.Request) {
+	canvasID := chi.URLParam(r, "id")
+
+	canvas, err := h.queries.GetCanvas(canvasID)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch canvas")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"palette":        canvas.Palette,
+		"palette_locked": canvas.PaletteLocked,
+	})
+}
+
+func (h *Handler) PutReplacePalette(w http.ResponseWriter, r *http.Request) {
+	updatePaletteDTO, ok := utils.DecodeJSONAndValidate[types.UpdatePaletteDTO](w, r)
+	if !ok {
+		return
+	}
+
+	h.editPalette(w, r, services.ReplaceColors(updatePaletteDTO.Colors))
+}
+
+func (h *Handler) PostAddPaletteColor(w http.ResponseWriter, r *http.Request) {
+	color, ok := decodePaletteColor(w, r)
+	if !ok {
+		return
+	}
+
+	h.editPalette(w, r, services.AddColor(color))
+}
+
+func (h *Handler) PutUpdatePaletteColor(w http.ResponseWriter, r *http.Request) {
+	index, err := strconv.Atoi(chi.URLParam(r, "index"))
+	if err != nil {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidColorIndex)
+		return
+	}
+
+	color, ok := decodePaletteColor(w, r)
+	if !ok {
+		return
+	}
+
+	h.editPalette(w, r, services.UpdateColor(index, color))
+}
+
+func (h *Handler) DeletePaletteColor(w http.ResponseWriter, r *http.Request) {
+	index, err := strconv.Atoi(chi.URLParam(r, "index"))
+	if err != nil {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidColorIndex)
+		return
+	}
+
+	h.editPalette(w, r, services.RemoveColor(index))
+}
+
+func (h *Handler) PutPaletteLock(w http.ResponseWriter, r *http.Request) {
+	userAccess := r.Context().Value(utils.AccessRuleKey).(types.UserAccess)
+	canvasID := chi.URLParam(r, "id")
+
+	// Locking the palette changes what everyone can paint, so it is a setting of the owner
+	if userAccess.AccessRole != types.Owner {
+		utils.ClientError(w, http.StatusUnauthorized, utils.ErrNotCanvasOwner)
+		return
+	}
+
+	updatePaletteLockDTO, ok := utils.DecodeJSONAndValidate[types.UpdatePaletteLockDTO](w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.queries.UpdatePaletteLocked(canvasID, updatePaletteLockDTO.Locked); err != nil {
+		utils.ServerError(w, r, err, "Failed to update palette lock")
+		return
+	}
+
+	h.websocket.SetPaletteLocked(canvasID, updatePaletteLockDTO.Locked)
+
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"message":        "Palette lock updated",
+		"palette_locked": updatePaletteLockDTO.Locked,
+	})
+}
+
+// editPalette applies the edit to the canvas palette through the websocket hub so that it is broadcast to the canvas room.
+func (h *Handler) editPalette(w http.ResponseWriter, r *http.Request, edit services.PaletteEdit) {
+	userAccess := r.Context().Value(utils.AccessRuleKey).(types.UserAccess)
+	canvasID := chi.URLParam(r, "id")
+
+	if userAccess.AccessRole == types.Viewer {
+		utils.ClientError(w, http.StatusUnauthorized, utils.ErrCanvasEditForbidden)
+		return
+	}
+
+	palette, err := h.websocket.EditPalette(canvasID, edit)
+	if err != nil {
+		switch {
+		case errors.Is(err, types.ErrPaletteFull):
+			utils.ClientError(w, http.StatusConflict, utils.ErrPaletteFull)
+		case errors.Is(err, types.ErrInvalidColorIndex):
+			utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidColorIndex)
+		default:
+			utils.ServerError(w, r, err, "Failed to update palette")
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"palette": palette,
+	})
+}
+
+func decodePaletteColor(w http.ResponseWriter, r *http.Request) (types.Pixel, bool) {
+	paletteColorDTO, ok := utils.DecodeJSONAndValidate[types.PaletteColorDTO](w, r)
+	if !ok {
+		return types.Pixel{}, false
+	}
+
+	color, err := types.ParseColor(paletteColorDTO.Color)
+	if err != nil {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidColor)
+		return types.Pixel{}, false
+	}
+
+	return color, true
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"image/gif"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/CDavidSV/Pixio/config"
+	"github.com/CDavidSV/Pixio/services"
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultTimelapseFPS = 10
+	maxTimelapseFPS     = 50
+)
+
+// GetReplay returns the flattened canvas as it was at the time given by the at query parameter.
+func (h *Handler) GetReplay(w http.ResponseWriter, r *http.Request) {
+	canvasID := chi.URLParam(r, "id")
+
+	at, ok := parseTimestamp(r.URL.Query().Get("at"))
+	if !ok {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidTimestamp)
+		return
+	}
+
+	canvas, err := h.queries.GetCanvas(canvasID)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch canvas")
+		return
+	}
+
+	// The operation log of a live canvas is only complete once its pending changes are saved
+	if err := h.websocket.Flush(canvasID); err != nil {
+		utils.ServerError(w, r, err, "Failed to save canvas changes")
+		return
+	}
+
+	pixelData, err := h.services.CanvasService.Replay(canvasID, canvas.Width, canvas.Height, at)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to replay canvas")
+		return
+	}
+
+	compressed, err := h.services.CanvasService.CompressPixelData(pixelData)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to encode canvas")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"width":      canvas.Width,
+		"height":     canvas.Height,
+		"timestamp":  at,
+		"pixel_data": compressed,
+	})
+}
+
+// GetTimelapse renders the history of the canvas as an animated GIF.
+// Accepts the canvas time between frames as interval, the frames per second as fps, an integer scale and a background color.
+func (h *Handler) GetTimelapse(w http.ResponseWriter, r *http.Request) {
+	canvasID := chi.URLParam(r, "id")
+	query := r.URL.Query()
+
+	canvas, err := h.queries.GetCanvas(canvasID)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch canvas")
+		return
+	}
+
+	opts := services.TimelapseOptions{
+		FPS:        defaultTimelapseFPS,
+		Scale:      1,
+		Background: types.Pixel{R: 255, G: 255, B: 255, A: 255},
+	}
+
+	if param := query.Get("interval"); param != "" {
+		opts.Interval, err = time.ParseDuration(param)
+		if err != nil || opts.Interval <= 0 {
+			utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidTimelapse)
+			return
+		}
+	}
+
+	if param := query.Get("fps"); param != "" {
+		opts.FPS, err = strconv.Atoi(param)
+		if err != nil || opts.FPS < 1 || opts.FPS > maxTimelapseFPS {
+			utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidTimelapse)
+			return
+		}
+	}
+
+	if param := query.Get("scale"); param != "" {
+		opts.Scale, err = strconv.Atoi(param)
+		if err != nil || opts.Scale < 1 || int(canvas.Width)*opts.Scale > config.TimelapseMaxSize || int(canvas.Height)*opts.Scale > config.TimelapseMaxSize {
+			utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidImageScale)
+			return
+		}
+	}
+
+	if param := query.Get("background"); param != "" {
+		opts.Background, err = types.ParseColor(param)
+		if err != nil {
+			utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidColor)
+			return
+		}
+	}
+
+	if err := h.websocket.Flush(canvasID); err != nil {
+		utils.ServerError(w, r, err, "Failed to save canvas changes")
+		return
+	}
+
+	timelapse, err := h.services.CanvasService.Timelapse(canvasID, canvas.Width, canvas.Height, opts)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to render timelapse")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/gif")
+	w.WriteHeader(http.StatusOK)
+	if err := gif.EncodeAll(w, timelapse); err != nil {
+		slog.Error("Failed to write timelapse", "canvasID", canvasID, "error", err)
+	}
+}
+
+// parseTimestamp parses a time in the RFC 3339 format or a Unix time in milliseconds.
+func parseTimestamp(s string) (time.Time, bool) {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms), true
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	return t, err == nil
+}
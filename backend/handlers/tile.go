@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/CDavidSV/Pixio/services"
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) GetTile(w http.ResponseWriter, r *http.Request) {
+	canvasID := chi.URLParam(r, "id")
+
+	tileX, errX := strconv.Atoi(chi.URLParam(r, "x"))
+	tileY, errY := strconv.Atoi(chi.URLParam(r, "y"))
+	if errX != nil || errY != nil {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidTile)
+		return
+	}
+
+	canvas, err := h.queries.GetCanvas(canvasID)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch canvas")
+		return
+	}
+
+	if !services.ValidTile(canvas.Width, canvas.Height, tileX, tileY) {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidTile)
+		return
+	}
+
+	// Live canvases have changes that are not saved yet
+	pixelData, ok := h.websocket.CanvasTile(canvasID, tileX, tileY)
+	if !ok {
+		pixelData, err = h.services.CanvasService.GetCanvasTile(canvasID, canvas.Width, canvas.Height, tileX, tileY)
+		if err != nil {
+			utils.ServerError(w, r, err, "Failed to fetch tile")
+			return
+		}
+	}
+
+	compressed, err := h.services.CanvasService.CompressPixelData(pixelData)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to encode tile")
+		return
+	}
+
+	x, y, width, height := services.TileBounds(canvas.Width, canvas.Height, tileX, tileY)
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"tile_x":     tileX,
+		"tile_y":     tileY,
+		"x":          x,
+		"y":          y,
+		"width":      width,
+		"height":     height,
+		"pixel_data": compressed,
+	})
+}
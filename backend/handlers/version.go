@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+func (h *Handler) GetVersions(w http.ResponseWriter, r *http.Request) {
+	canvasID := chi.URLParam(r, "id")
+
+	versions, err := h.queries.GetVersions(canvasID)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to fetch versions")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, versions)
+}
+
+// GetVersion downloads the version with the compressed pixel data of the flattened image and of every layer.
+func (h *Handler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	canvasID := chi.URLParam(r, "id")
+	versionID := chi.URLParam(r, "versionID")
+
+	if len(versionID) != 26 {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidID)
+		return
+	}
+
+	version, err := h.queries.GetVersion(canvasID, versionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			utils.ClientError(w, http.StatusNotFound, utils.ErrVersionNotFound)
+			return
+		}
+
+		utils.ServerError(w, r, err, "Failed to fetch version")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, version)
+}
+
+func (h *Handler) PostCreateVersion(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(utils.UserIDKey).(string)
+	userAccess := r.Context().Value(utils.AccessRuleKey).(types.UserAccess)
+	canvasID := chi.URLParam(r, "id")
+
+	if userAccess.AccessRole == types.Viewer {
+		utils.ClientError(w, http.StatusUnauthorized, utils.ErrCanvasEditForbidden)
+		return
+	}
+
+	createVersionDTO, ok := utils.DecodeJSONAndValidate[types.CreateVersionDTO](w, r)
+	if !ok {
+		return
+	}
+
+	version, err := h.websocket.SaveVersion(canvasID, createVersionDTO.Label, userID)
+	if err != nil {
+		utils.ServerError(w, r, err, "Failed to save version")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, version)
+}
+
+func (h *Handler) PostRestoreVersion(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(utils.UserIDKey).(string)
+	userAccess := r.Context().Value(utils.AccessRuleKey).(types.UserAccess)
+	canvasID := chi.URLParam(r, "id")
+	versionID := chi.URLParam(r, "versionID")
+
+	if len(versionID) != 26 {
+		utils.ClientError(w, http.StatusBadRequest, utils.ErrInvalidID)
+		return
+	}
+
+	if userAccess.AccessRole == types.Viewer {
+		utils.ClientError(w, http.StatusUnauthorized, utils.ErrCanvasEditForbidden)
+		return
+	}
+
+	if err := h.websocket.RestoreVersion(canvasID, versionID, userID); err != nil {
+		if errors.Is(err, types.ErrVersionNotFound) {
+			utils.ClientError(w, http.StatusNotFound, utils.ErrVersionNotFound)
+			return
+		}
+
+		utils.ServerError(w, r, err, "Failed to restore version")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, types.Map{
+		"message":    "Version restored successfully",
+		"version_id": versionID,
+	})
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/CDavidSV/Pixio/types"
 	"github.com/CDavidSV/Pixio/utils"
 	"github.com/go-chi/chi/v5"
-	"github.com/jackc/pgx/v5"
 )
 
 func (m *Middleware) AuthorizeCanvasAccess(next http.Handler) http.Handler {
@@ -23,30 +22,16 @@ func (m *Middleware) AuthorizeCanvasAccess(next http.Handler) http.Handler {
 			return
 		}
 
-		canvasAccessType, canvasAccessRole, err := m.queries.GetCanvasLinkAccess(canvasID)
+		userAccess, err := m.services.CanvasService.GetUserCanvasAccess(canvasID, userID)
 		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
+			if errors.Is(err, types.ErrCanvasDoesNotExist) {
 				utils.WriteJSON(w, http.StatusNotFound, types.ErrorResponse{
 					Error: "This canvas does not exist",
 				})
 				return
 			}
 
-			utils.ServerError(w, r, err, "Unable to fetch canvas access")
-			return
-		}
-
-		ctx := context.WithValue(r.Context(), utils.UserIDKey, userID)
-		if canvasAccessType == types.WithLink {
-			ctx = context.WithValue(ctx, utils.AccessRuleKey, canvasAccessRole)
-			r = r.WithContext(ctx)
-
-			next.ServeHTTP(w, r)
-		}
-
-		userAccess, err := m.queries.GetUserAccess(canvasID, types.CanvasObject, userID)
-		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
+			if errors.Is(err, types.ErrUserAccessDenied) {
 				utils.WriteJSON(w, http.StatusUnauthorized, types.ErrorResponse{
 					Error: "You do not have permission to access this canvas",
 				})
@@ -57,7 +42,7 @@ func (m *Middleware) AuthorizeCanvasAccess(next http.Handler) http.Handler {
 			return
 		}
 
-		ctx = context.WithValue(ctx, utils.AccessRuleKey, userAccess.AccessRole)
+		ctx := context.WithValue(r.Context(), utils.AccessRuleKey, userAccess)
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
@@ -3,59 +3,192 @@ package services
 import (
 	"bytes"
 	"compress/zlib"
+	"errors"
+	"fmt"
 
 	"github.com/CDavidSV/Pixio/data"
 	"github.com/CDavidSV/Pixio/types"
+	"github.com/jackc/pgx/v5"
 )
 
 type CanvasService struct {
 	queries *data.Queries
 }
 
+// Canvas data starts with a header made of canvasDataMagic, the format version and the pixel encoding, followed by the zlib compressed pixels.
+// Data without the header is in the original format: the RGBA bytes of every pixel compressed with zlib.
+const (
+	canvasDataMagic   = "PXIO"
+	canvasDataVersion = 1
+)
+
+// Pixel encodings of the canvas data
+const (
+	encodingRGBA    byte = iota // 4 bytes per pixel
+	encodingIndexed             // Number of colors - 1, the RGBA bytes of each color and a 1 byte color index per pixel
+)
+
+// CompressPixelData encodes the pixels with a color index when they use at most 256 colors, falling back to RGBA otherwise.
 func (s *CanvasService) CompressPixelData(pixelData []types.Pixel) ([]byte, error) {
-	var rawData bytes.Buffer
+	encoding := encodingIndexed
+	indexes := make(map[types.Pixel]int)
+	var palette []types.Pixel
 	for _, pixel := range pixelData {
-		rawData.WriteByte(pixel.R)
-		rawData.WriteByte(pixel.G)
-		rawData.WriteByte(pixel.B)
-		rawData.WriteByte(pixel.A)
+		if _, ok := indexes[pixel]; ok {
+			continue
+		}
+
+		if len(palette) == 256 {
+			encoding = encodingRGBA
+			break
+		}
+
+		indexes[pixel] = len(palette)
+		palette = append(palette, pixel)
+	}
+
+	if len(palette) == 0 {
+		encoding = encodingRGBA
+	}
+
+	var rawData bytes.Buffer
+	if encoding == encodingIndexed {
+		rawData.Grow(1 + len(palette)*4 + len(pixelData))
+		rawData.WriteByte(byte(len(palette) - 1))
+		for _, color := range palette {
+			rawData.Write([]byte{color.R, color.G, color.B, color.A})
+		}
+
+		for _, pixel := range pixelData {
+			rawData.WriteByte(byte(indexes[pixel]))
+		}
+	} else {
+		rawData.Grow(len(pixelData) * 4)
+		for _, pixel := range pixelData {
+			rawData.Write([]byte{pixel.R, pixel.G, pixel.B, pixel.A})
+		}
 	}
 
 	var compressed bytes.Buffer
+	compressed.WriteString(canvasDataMagic)
+	compressed.WriteByte(canvasDataVersion)
+	compressed.WriteByte(encoding)
+
 	zw := zlib.NewWriter(&compressed)
-	defer zw.Close()
 	if _, err := zw.Write(rawData.Bytes()); err != nil {
 		return []byte{}, err
 	}
 
+	// The writer must be closed before reading the buffer so that all of the compressed data is flushed
+	if err := zw.Close(); err != nil {
+		return []byte{}, err
+	}
+
 	return compressed.Bytes(), nil
 }
 
+// LoadCanvas decodes canvas data in any of the supported formats.
 func (s *CanvasService) LoadCanvas(compressed []byte) ([]types.Pixel, error) {
-	var pixelArr []types.Pixel
-	var decompressed bytes.Buffer
+	encoding := encodingRGBA
+	headerSize := len(canvasDataMagic) + 2
+	if len(compressed) >= headerSize && string(compressed[:len(canvasDataMagic)]) == canvasDataMagic {
+		if version := compressed[len(canvasDataMagic)]; version != canvasDataVersion {
+			return nil, fmt.Errorf("unsupported canvas data version %d", version)
+		}
 
+		encoding = compressed[len(canvasDataMagic)+1]
+		compressed = compressed[headerSize:]
+	}
+
+	var decompressed bytes.Buffer
 	zr, err := zlib.NewReader(bytes.NewReader(compressed))
 	if err != nil {
-		return pixelArr, err
+		return nil, err
 	}
 
 	_, err = decompressed.ReadFrom(zr)
 	if err != nil {
-		return pixelArr, err
+		return nil, err
 	}
 	zr.Close()
 
-	pixelBytes := decompressed.Bytes()
-	pixelArr = make([]types.Pixel, len(pixelBytes)/4)
-	for i := range len(pixelArr) {
-		pixelArr[i] = types.Pixel{
-			R: pixelBytes[i*4],
-			G: pixelBytes[i*4+1],
-			B: pixelBytes[i*4+2],
-			A: pixelBytes[i*4+3],
+	data := decompressed.Bytes()
+	switch encoding {
+	case encodingRGBA:
+		pixelArr := make([]types.Pixel, len(data)/4)
+		for i := range len(pixelArr) {
+			pixelArr[i] = types.Pixel{
+				R: data[i*4],
+				G: data[i*4+1],
+				B: data[i*4+2],
+				A: data[i*4+3],
+			}
+		}
+
+		return pixelArr, nil
+	case encodingIndexed:
+		if len(data) == 0 {
+			return nil, fmt.Errorf("indexed canvas data is missing its palette")
+		}
+
+		colors := int(data[0]) + 1
+		if len(data) < 1+colors*4 {
+			return nil, fmt.Errorf("indexed canvas data palette is truncated")
+		}
+
+		palette := make([]types.Pixel, colors)
+		for i := range palette {
+			offset := 1 + i*4
+			palette[i] = types.Pixel{R: data[offset], G: data[offset+1], B: data[offset+2], A: data[offset+3]}
+		}
+
+		indexes := data[1+colors*4:]
+		pixelArr := make([]types.Pixel, len(indexes))
+		for i, index := range indexes {
+			if int(index) >= colors {
+				return nil, fmt.Errorf("color index %d is outside of the palette", index)
+			}
+
+			pixelArr[i] = palette[index]
+		}
+
+		return pixelArr, nil
+	default:
+		return nil, fmt.Errorf("unsupported canvas data encoding %d", encoding)
+	}
+}
+
+// GetUserCanvasAccess returns the effective access of the user on the canvas.
+// Canvases shared by link grant their link access role to every user, unless the user has a better explicit role.
+func (s *CanvasService) GetUserCanvasAccess(canvasID, userID string) (types.UserAccess, error) {
+	linkAccessType, linkAccessRole, err := s.queries.GetCanvasLinkAccess(canvasID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return types.UserAccess{}, types.ErrCanvasDoesNotExist
 		}
+
+		return types.UserAccess{}, err
+	}
+
+	userAccess, err := s.queries.GetUserAccess(canvasID, types.CanvasObject, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return types.UserAccess{}, err
+	}
+	hasExplicitAccess := err == nil
+
+	// Lower roles have more privileges
+	if linkAccessType == types.WithLink && (!hasExplicitAccess || linkAccessRole < userAccess.AccessRole) {
+		return types.UserAccess{
+			ObjectID:   canvasID,
+			ObjectType: types.CanvasObject,
+			UserID:     userID,
+			AccessRole: linkAccessRole,
+		}, nil
+	}
+
+	if !hasExplicitAccess {
+		return types.UserAccess{}, types.ErrUserAccessDenied
 	}
 
-	return pixelArr, nil
+	return userAccess, nil
 }
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"compress/zlib"
+	"slices"
+	"testing"
+
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// testPixels returns n pixels using the given number of distinct colors.
+func testPixels(n, colors int) []types.Pixel {
+	pixels := make([]types.Pixel, n)
+	for i := range pixels {
+		c := i % colors
+		pixels[i] = types.Pixel{R: uint8(c), G: uint8(c >> 8), B: 10, A: 255}
+	}
+
+	return pixels
+}
+
+// compress returns the data compressed with zlib.
+func compress(t *testing.T, data []byte) []byte {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.Bytes()
+}
+
+func TestPixelDataRoundTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		pixels       []types.Pixel
+		wantEncoding byte
+	}{
+		{name: "empty", pixels: []types.Pixel{}, wantEncoding: encodingRGBA},
+		{name: "transparent", pixels: make([]types.Pixel, 100), wantEncoding: encodingIndexed},
+		{name: "few colors", pixels: testPixels(1000, 3), wantEncoding: encodingIndexed},
+		{name: "256 colors", pixels: testPixels(1000, 256), wantEncoding: encodingIndexed},
+		{name: "257 colors", pixels: testPixels(1000, 257), wantEncoding: encodingRGBA},
+		{name: "semi transparent", pixels: []types.Pixel{{R: 1, G: 2, B: 3, A: 4}, {}, {R: 255, G: 255, B: 255, A: 128}}, wantEncoding: encodingIndexed},
+	}
+
+	s := &CanvasService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := s.CompressPixelData(tt.pixels)
+			if err != nil {
+				t.Fatalf("CompressPixelData() error = %v", err)
+			}
+
+			if !bytes.HasPrefix(data, []byte(canvasDataMagic)) {
+				t.Fatalf("CompressPixelData() data doesn't start with %q", canvasDataMagic)
+			}
+			if version := data[len(canvasDataMagic)]; version != canvasDataVersion {
+				t.Errorf("version = %d, want %d", version, canvasDataVersion)
+			}
+			if encoding := data[len(canvasDataMagic)+1]; encoding != tt.wantEncoding {
+				t.Errorf("encoding = %d, want %d", encoding, tt.wantEncoding)
+			}
+
+			got, err := s.LoadCanvas(data)
+			if err != nil {
+				t.Fatalf("LoadCanvas() error = %v", err)
+			}
+
+			if !slices.Equal(got, tt.pixels) {
+				t.Errorf("LoadCanvas() returned %d pixels that don't match the %d compressed pixels", len(got), len(tt.pixels))
+			}
+		})
+	}
+}
+
+func TestLoadCanvas(t *testing.T) {
+	header := func(version, encoding byte) []byte {
+		return append([]byte(canvasDataMagic), version, encoding)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []types.Pixel
+		wantErr bool
+	}{
+		{
+			name: "original format without a header",
+			data: compress(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}),
+			want: []types.Pixel{{R: 1, G: 2, B: 3, A: 4}, {R: 5, G: 6, B: 7, A: 8}},
+		},
+		{
+			name: "indexed",
+			data: append(header(canvasDataVersion, encodingIndexed), compress(t, []byte{1, 1, 2, 3, 4, 5, 6, 7, 8, 0, 1, 1})...),
+			want: []types.Pixel{{R: 1, G: 2, B: 3, A: 4}, {R: 5, G: 6, B: 7, A: 8}, {R: 5, G: 6, B: 7, A: 8}},
+		},
+		{
+			name:    "unsupported version",
+			data:    append(header(canvasDataVersion+1, encodingRGBA), compress(t, []byte{1, 2, 3, 4})...),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported encoding",
+			data:    append(header(canvasDataVersion, 9), compress(t, []byte{1, 2, 3, 4})...),
+			wantErr: true,
+		},
+		{
+			name:    "indexed without a palette",
+			data:    append(header(canvasDataVersion, encodingIndexed), compress(t, nil)...),
+			wantErr: true,
+		},
+		{
+			name:    "indexed with a truncated palette",
+			data:    append(header(canvasDataVersion, encodingIndexed), compress(t, []byte{1, 1, 2, 3, 4})...),
+			wantErr: true,
+		},
+		{
+			name:    "index outside of the palette",
+			data:    append(header(canvasDataVersion, encodingIndexed), compress(t, []byte{0, 1, 2, 3, 4, 0, 1})...),
+			wantErr: true,
+		},
+		{
+			name:    "not compressed",
+			data:    []byte{1, 2, 3, 4},
+			wantErr: true,
+		},
+	}
+
+	s := &CanvasService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.LoadCanvas(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadCanvas() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("LoadCanvas() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
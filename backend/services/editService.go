@@ -0,0 +1,272 @@
+package services
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/CDavidSV/Pixio/config"
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// PixelEdits maps the index of every pixel a user changed to the time of the user's last change.
+type PixelEdits map[int]time.Time
+
+// EncodeEdits compresses the changed pixel indexes along with the time of each change.
+// Indexes are stored as deltas from the previous index and times as milliseconds before the returned most recent change.
+func (s *CanvasService) EncodeEdits(edits PixelEdits) ([]byte, time.Time, error) {
+	indexes := slices.Sorted(maps.Keys(edits))
+
+	var latest time.Time
+	for _, t := range edits {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	latest = latest.Truncate(time.Millisecond)
+
+	buf := binary.AppendUvarint(nil, uint64(len(indexes)))
+	previous := 0
+	for _, index := range indexes {
+		buf = binary.AppendUvarint(buf, uint64(index-previous))
+		buf = binary.AppendUvarint(buf, uint64(max(latest.Sub(edits[index]).Milliseconds(), 0)))
+		previous = index
+	}
+
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	if _, err := w.Write(buf); err != nil {
+		return nil, latest, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, latest, err
+	}
+
+	return b.Bytes(), latest, nil
+}
+
+// decodeEdits calls fn with every pixel index and change time of an edit batch compressed with EncodeEdits.
+func (s *CanvasService) decodeEdits(data []byte, timestamp time.Time, fn func(index int, t time.Time)) error {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(raw)
+	count, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return err
+	}
+
+	index := 0
+	for range count {
+		delta, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return fmt.Errorf("failed to read pixel index: %w", err)
+		}
+
+		age, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return fmt.Errorf("failed to read change time: %w", err)
+		}
+
+		index += int(delta)
+		fn(index, timestamp.Add(-time.Duration(age)*time.Millisecond))
+	}
+
+	return nil
+}
+
+// SaveEdits stores the pixels each user changed as one edit batch per user.
+func (s *CanvasService) SaveEdits(canvasID string, edits map[string]PixelEdits) error {
+	batches := make([]types.Edit, 0, len(edits))
+	for userID, pixels := range edits {
+		if len(pixels) == 0 {
+			continue
+		}
+
+		data, latest, err := s.EncodeEdits(pixels)
+		if err != nil {
+			return err
+		}
+
+		batches = append(batches, types.Edit{
+			CanvasID:   canvasID,
+			UserID:     userID,
+			PixelCount: len(pixels),
+			Data:       data,
+			Timestamp:  latest,
+		})
+	}
+
+	if len(batches) == 0 {
+		return nil
+	}
+
+	return s.queries.CreateEdits(canvasID, batches)
+}
+
+// editScan calls fn with saved edit batches of a canvas, newest first, until fn returns false.
+type editScan func(fn func(edit types.Edit) bool) error
+
+// recentEdits scans the most recent edit batches of the canvas, the history before config.EditScanMaxBatches is left out.
+func (s *CanvasService) recentEdits(canvasID string) editScan {
+	return func(fn func(edit types.Edit) bool) error {
+		return s.queries.ScanEdits(canvasID, config.EditScanMaxBatches, fn)
+	}
+}
+
+// Blame returns the most recent change of any pixel in the area, or nil if none of its pixels were changed.
+// pending holds the edits of a live room that are not saved yet, they are more recent than every saved edit.
+func (s *CanvasService) Blame(canvasID string, width uint16, x, y, w, h int, pending map[string]PixelEdits) (*types.Blame, error) {
+	blame, err := s.latestChange(s.recentEdits(canvasID), width, x, y, w, h, pending)
+	if err != nil || blame == nil {
+		return nil, err
+	}
+
+	user, err := s.queries.GetUserByID(blame.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	blame.Username = user.Username
+	blame.AvatarURL = user.AvatarURL
+	return blame, nil
+}
+
+// latestChange finds the most recent change inside the area for Blame, reading saved batches until none can hold a more recent change.
+func (s *CanvasService) latestChange(scan editScan, width uint16, x, y, w, h int, pending map[string]PixelEdits) (*types.Blame, error) {
+	var blame *types.Blame
+	consider := func(userID string, index int, t time.Time) {
+		px, py := index%int(width), index/int(width)
+		if px < x || py < y || px >= x+w || py >= y+h {
+			return
+		}
+
+		if blame == nil || t.After(blame.Timestamp) {
+			blame = &types.Blame{UserID: userID, X: px, Y: py, Timestamp: t}
+		}
+	}
+
+	for userID, edits := range pending {
+		for index, t := range edits {
+			consider(userID, index, t)
+		}
+	}
+
+	var decodeErr error
+	err := scan(func(edit types.Edit) bool {
+		// Batches are saved in order, so an older batch can't hold a more recent change than the one found
+		if blame != nil && edit.Timestamp.Before(blame.Timestamp) {
+			return false
+		}
+
+		decodeErr = s.decodeEdits(edit.Data, edit.Timestamp, func(index int, t time.Time) {
+			consider(edit.UserID, index, t)
+		})
+		return decodeErr == nil
+	})
+	if err = errors.Join(err, decodeErr); err != nil {
+		return nil, err
+	}
+
+	return blame, nil
+}
+
+// Contributions summarizes the recent changes of every collaborator of the canvas, ordered by the number of pixels they were the last to change.
+// pending holds the edits of a live room that are not saved yet. Only the batches read by recentEdits are counted.
+func (s *CanvasService) Contributions(canvasID string, width, height uint16, pending map[string]PixelEdits) ([]types.Contribution, error) {
+	contributions, err := s.summarizeEdits(s.recentEdits(canvasID), width, height, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.queries.GetUsersByIDs(slices.Collect(maps.Keys(contributions)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		contributions[user.ID].Username = user.Username
+		contributions[user.ID].AvatarURL = user.AvatarURL
+	}
+
+	summary := make([]types.Contribution, 0, len(contributions))
+	for _, c := range contributions {
+		summary = append(summary, *c)
+	}
+	slices.SortFunc(summary, func(a, b types.Contribution) int { return b.Pixels - a.Pixels })
+
+	return summary, nil
+}
+
+// summarizeEdits counts the changes of each user for Contributions. Changes are read newest first,
+// so the first change of a pixel belongs to the user who was the last to change it.
+func (s *CanvasService) summarizeEdits(scan editScan, width, height uint16, pending map[string]PixelEdits) (map[string]*types.Contribution, error) {
+	contributions := make(map[string]*types.Contribution)
+	contribution := func(userID string) *types.Contribution {
+		c, ok := contributions[userID]
+		if !ok {
+			c = &types.Contribution{UserID: userID}
+			contributions[userID] = c
+		}
+		return c
+	}
+
+	claimed := make([]bool, int(width)*int(height))
+	apply := func(c *types.Contribution, index int, t time.Time) {
+		if index < len(claimed) && !claimed[index] {
+			claimed[index] = true
+			c.Pixels++
+		}
+		if t.After(c.LastEditAt) {
+			c.LastEditAt = t
+		}
+	}
+
+	// Pending edits of different users may change the same pixel, so they are applied from the most recent one
+	type pendingEdit struct {
+		userID string
+		index  int
+		t      time.Time
+	}
+	var ordered []pendingEdit
+	for userID, edits := range pending {
+		contribution(userID).EditedPixels += len(edits)
+		for index, t := range edits {
+			ordered = append(ordered, pendingEdit{userID, index, t})
+		}
+	}
+	slices.SortFunc(ordered, func(a, b pendingEdit) int { return b.t.Compare(a.t) })
+	for _, edit := range ordered {
+		apply(contributions[edit.userID], edit.index, edit.t)
+	}
+
+	var decodeErr error
+	err := scan(func(edit types.Edit) bool {
+		c := contribution(edit.UserID)
+		c.EditedPixels += edit.PixelCount
+
+		decodeErr = s.decodeEdits(edit.Data, edit.Timestamp, func(index int, t time.Time) {
+			apply(c, index, t)
+		})
+		return decodeErr == nil
+	})
+	if err = errors.Join(err, decodeErr); err != nil {
+		return nil, err
+	}
+
+	return contributions, nil
+}
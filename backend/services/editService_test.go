@@ -0,0 +1,251 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CDavidSV/Pixio/types"
+)
+
+func TestEditsRoundTrip(t *testing.T) {
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		edits      PixelEdits
+		wantLatest time.Time
+	}{
+		{name: "no edits", edits: PixelEdits{}},
+		{name: "single edit", edits: PixelEdits{42: base}, wantLatest: base},
+		{
+			name:       "unordered indexes",
+			edits:      PixelEdits{900: base, 3: base.Add(time.Second), 70000: base.Add(-time.Hour)},
+			wantLatest: base.Add(time.Second),
+		},
+		{
+			name:       "sub millisecond times are truncated",
+			edits:      PixelEdits{0: base.Add(1500 * time.Microsecond), 1: base},
+			wantLatest: base.Add(time.Millisecond),
+		},
+	}
+
+	s := &CanvasService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, latest, err := s.EncodeEdits(tt.edits)
+			if err != nil {
+				t.Fatalf("EncodeEdits() error = %v", err)
+			}
+
+			if len(tt.edits) > 0 && !latest.Equal(tt.wantLatest) {
+				t.Errorf("EncodeEdits() latest = %v, want %v", latest, tt.wantLatest)
+			}
+
+			decoded := make(PixelEdits)
+			previous := -1
+			err = s.decodeEdits(data, latest, func(index int, ts time.Time) {
+				if index <= previous {
+					t.Errorf("index %d decoded after %d, indexes must be increasing", index, previous)
+				}
+				previous = index
+				decoded[index] = ts
+			})
+			if err != nil {
+				t.Fatalf("decodeEdits() error = %v", err)
+			}
+
+			if len(decoded) != len(tt.edits) {
+				t.Fatalf("decodeEdits() returned %d edits, want %d", len(decoded), len(tt.edits))
+			}
+
+			// Times are stored with millisecond precision
+			for index, want := range tt.edits {
+				got, ok := decoded[index]
+				if !ok {
+					t.Errorf("pixel %d is missing", index)
+					continue
+				}
+
+				if !got.Equal(want.Truncate(time.Millisecond)) {
+					t.Errorf("pixel %d changed at %v, want %v", index, got, want.Truncate(time.Millisecond))
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeEditsErrors(t *testing.T) {
+	s := &CanvasService{}
+	data, latest, err := s.EncodeEdits(PixelEdits{1: time.Now(), 2: time.Now()})
+	if err != nil {
+		t.Fatalf("EncodeEdits() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "not compressed", data: []byte{1, 2, 3}},
+		{name: "truncated", data: data[:len(data)-4]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.decodeEdits(tt.data, latest, func(int, time.Time) {}); err == nil {
+				t.Error("decodeEdits() error = nil, want an error")
+			}
+		})
+	}
+}
+
+// testEdit encodes the changes of a user into an edit batch.
+func testEdit(t *testing.T, s *CanvasService, userID string, edits PixelEdits) types.Edit {
+	t.Helper()
+
+	data, latest, err := s.EncodeEdits(edits)
+	if err != nil {
+		t.Fatalf("EncodeEdits() error = %v", err)
+	}
+
+	return types.Edit{UserID: userID, PixelCount: len(edits), Data: data, Timestamp: latest}
+}
+
+// testScan scans the batches, given newest first, and counts the batches that were read.
+func testScan(batches []types.Edit, read *int) editScan {
+	return func(fn func(edit types.Edit) bool) error {
+		for _, edit := range batches {
+			*read++
+			if !fn(edit) {
+				return nil
+			}
+		}
+		return nil
+	}
+}
+
+func TestLatestChange(t *testing.T) {
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := &CanvasService{}
+
+	// A 4x2 canvas, batches are newest first
+	batches := []types.Edit{
+		testEdit(t, s, "c", PixelEdits{0: base.Add(3 * time.Minute)}),
+		testEdit(t, s, "b", PixelEdits{1: base.Add(2 * time.Minute), 5: base.Add(90 * time.Second)}),
+		testEdit(t, s, "a", PixelEdits{1: base, 6: base.Add(time.Minute)}),
+		testEdit(t, s, "a", PixelEdits{7: base.Add(-time.Hour)}),
+	}
+
+	tests := []struct {
+		name       string
+		x, y, w, h int
+		pending    map[string]PixelEdits
+		want       *types.Blame
+		wantRead   int
+	}{
+		{
+			name: "newest batch stops the scan",
+			x:    0, y: 0, w: 1, h: 1,
+			want:     &types.Blame{UserID: "c", X: 0, Y: 0, Timestamp: base.Add(3 * time.Minute)},
+			wantRead: 2,
+		},
+		{
+			name: "newest change of the pixel",
+			x:    1, y: 0, w: 1, h: 1,
+			want:     &types.Blame{UserID: "b", X: 1, Y: 0, Timestamp: base.Add(2 * time.Minute)},
+			wantRead: 3,
+		},
+		{
+			name: "newest change in the area",
+			x:    1, y: 1, w: 3, h: 1,
+			want:     &types.Blame{UserID: "b", X: 1, Y: 1, Timestamp: base.Add(90 * time.Second)},
+			wantRead: 3,
+		},
+		{
+			name: "unchanged pixel reads every batch",
+			x:    3, y: 0, w: 1, h: 1,
+			wantRead: 4,
+		},
+		{
+			name: "pending edits are newer than saved batches",
+			x:    1, y: 0, w: 1, h: 1,
+			pending:  map[string]PixelEdits{"d": {1: base.Add(time.Hour)}},
+			want:     &types.Blame{UserID: "d", X: 1, Y: 0, Timestamp: base.Add(time.Hour)},
+			wantRead: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			read := 0
+			got, err := s.latestChange(testScan(batches, &read), 4, tt.x, tt.y, tt.w, tt.h, tt.pending)
+			if err != nil {
+				t.Fatalf("latestChange() error = %v", err)
+			}
+
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("latestChange() = %v, want %v", got, tt.want)
+			}
+
+			if read != tt.wantRead {
+				t.Errorf("latestChange() read %d batches, want %d", read, tt.wantRead)
+			}
+		})
+	}
+}
+
+func TestSummarizeEdits(t *testing.T) {
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	s := &CanvasService{}
+
+	// A 2x2 canvas, batches are newest first
+	batches := []types.Edit{
+		testEdit(t, s, "b", PixelEdits{1: base.Add(2 * time.Minute)}),
+		testEdit(t, s, "a", PixelEdits{0: base, 1: base.Add(time.Minute), 2: base}),
+	}
+
+	tests := []struct {
+		name    string
+		pending map[string]PixelEdits
+		want    map[string]types.Contribution
+	}{
+		{
+			name: "newer batches take the pixels",
+			want: map[string]types.Contribution{
+				"a": {UserID: "a", Pixels: 2, EditedPixels: 3, LastEditAt: base.Add(time.Minute)},
+				"b": {UserID: "b", Pixels: 1, EditedPixels: 1, LastEditAt: base.Add(2 * time.Minute)},
+			},
+		},
+		{
+			name: "pending edits are newer than saved batches",
+			pending: map[string]PixelEdits{
+				"c": {0: base.Add(time.Hour)},
+				"b": {0: base.Add(2 * time.Hour), 3: base.Add(time.Hour)},
+			},
+			want: map[string]types.Contribution{
+				"a": {UserID: "a", Pixels: 1, EditedPixels: 3, LastEditAt: base.Add(time.Minute)},
+				"b": {UserID: "b", Pixels: 3, EditedPixels: 3, LastEditAt: base.Add(2 * time.Hour)},
+				"c": {UserID: "c", Pixels: 0, EditedPixels: 1, LastEditAt: base.Add(time.Hour)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			read := 0
+			got, err := s.summarizeEdits(testScan(batches, &read), 2, 2, tt.pending)
+			if err != nil {
+				t.Fatalf("summarizeEdits() error = %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("summarizeEdits() returned %d users, want %d", len(got), len(tt.want))
+			}
+
+			for userID, want := range tt.want {
+				if c, ok := got[userID]; !ok || *c != want {
+					t.Errorf("contribution of %s = %v, want %v", userID, c, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// ExportOptions controls how a canvas is rendered as an image.
+type ExportOptions struct {
+	Scale      int          // Size in image pixels of each canvas pixel
+	Background *types.Pixel // Drawn behind the canvas, nil keeps transparent pixels transparent
+	Grid       *types.Pixel // Color of the lines drawn between canvas pixels, nil draws no grid
+}
+
+// RenderImage draws the pixels of the canvas scaled by nearest neighbour.
+// The grid is drawn over the first row and column of every scaled pixel, plus one more row and column that close the grid on the right and bottom.
+func (s *CanvasService) RenderImage(pixelData []types.Pixel, width, height uint16, opts ExportOptions) *image.NRGBA {
+	w, h := int(width)*opts.Scale, int(height)*opts.Scale
+	if opts.Grid != nil {
+		w, h = w+1, h+1
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			var p types.Pixel
+			cx, cy := x/opts.Scale, y/opts.Scale
+			if cx < int(width) && cy < int(height) {
+				p = pixelData[cy*int(width)+cx]
+			}
+
+			if opts.Background != nil {
+				p = blendPixel(*opts.Background, p, 1)
+			}
+
+			if opts.Grid != nil && (x%opts.Scale == 0 || y%opts.Scale == 0) {
+				p = blendPixel(p, *opts.Grid, 1)
+			}
+
+			img.SetNRGBA(x, y, color.NRGBA{R: p.R, G: p.G, B: p.B, A: p.A})
+		}
+	}
+
+	return img
+}
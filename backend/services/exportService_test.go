@@ -0,0 +1,117 @@
+package services
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/CDavidSV/Pixio/types"
+)
+
+func TestRenderImage(t *testing.T) {
+	red := types.Pixel{R: 255, A: 255}
+	white := types.Pixel{R: 255, G: 255, B: 255, A: 255}
+	black := types.Pixel{A: 255}
+
+	// The canvas is a red pixel next to a transparent one
+	pixels := []types.Pixel{red, {}}
+	colors := map[byte]types.Pixel{'r': red, 'w': white, '#': black, '.': {}}
+
+	tests := []struct {
+		name string
+		opts ExportOptions
+		want []string
+	}{
+		{
+			name: "original size",
+			opts: ExportOptions{Scale: 1},
+			want: []string{"r."},
+		},
+		{
+			name: "scaled",
+			opts: ExportOptions{Scale: 2},
+			want: []string{
+				"rr..",
+				"rr..",
+			},
+		},
+		{
+			name: "background",
+			opts: ExportOptions{Scale: 1, Background: &white},
+			want: []string{"rw"},
+		},
+		{
+			name: "grid",
+			opts: ExportOptions{Scale: 2, Grid: &black},
+			want: []string{
+				"#####",
+				"#r#.#",
+				"#####",
+			},
+		},
+		{
+			name: "grid with a larger scale",
+			opts: ExportOptions{Scale: 3, Grid: &black},
+			want: []string{
+				"#######",
+				"#rr#..#",
+				"#rr#..#",
+				"#######",
+			},
+		},
+		{
+			name: "grid over the background",
+			opts: ExportOptions{Scale: 2, Background: &white, Grid: &black},
+			want: []string{
+				"#####",
+				"#r#w#",
+				"#####",
+			},
+		},
+	}
+
+	s := &CanvasService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := s.RenderImage(pixels, 2, 1, tt.opts)
+
+			bounds := img.Bounds()
+			if bounds.Dx() != len(tt.want[0]) || bounds.Dy() != len(tt.want) {
+				t.Fatalf("RenderImage() size = %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), len(tt.want[0]), len(tt.want))
+			}
+
+			for y, row := range tt.want {
+				for x := range len(row) {
+					p := colors[row[x]]
+					want := color.NRGBA{R: p.R, G: p.G, B: p.B, A: p.A}
+					if got := img.NRGBAAt(x, y); got != want {
+						t.Errorf("pixel (%d, %d) = %v, want %v", x, y, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRenderImageSemiTransparentGrid(t *testing.T) {
+	// A semi transparent grid is blended over the canvas instead of replacing it
+	grid := types.Pixel{A: 128}
+	img := (&CanvasService{}).RenderImage([]types.Pixel{{R: 255, G: 255, B: 255, A: 255}}, 1, 1, ExportOptions{Scale: 2, Grid: &grid})
+
+	tests := []struct {
+		name string
+		x, y int
+		want color.NRGBA
+	}{
+		{name: "grid line", x: 0, y: 0, want: color.NRGBA{R: 127, G: 127, B: 127, A: 255}},
+		{name: "canvas pixel", x: 1, y: 1, want: color.NRGBA{R: 255, G: 255, B: 255, A: 255}},
+		{name: "closing grid line", x: 2, y: 2, want: color.NRGBA{A: 128}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := img.NRGBAAt(tt.x, tt.y); got != tt.want {
+				t.Errorf("pixel (%d, %d) = %v, want %v", tt.x, tt.y, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"cmp"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/CDavidSV/Pixio/config"
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// ResizeFilter selects how an imported image is resampled to its target size.
+type ResizeFilter string
+
+const (
+	NearestFilter ResizeFilter = "nearest" // Keeps hard pixel edges, suited for pixel art
+	BoxFilter     ResizeFilter = "box"     // Averages the covered pixels when shrinking, suited for photos and painted references
+)
+
+// DecodeImage decodes a PNG, GIF or JPEG image into pixels, animated GIFs are decoded from their first frame.
+func (s *CanvasService) DecodeImage(r io.ReadSeeker) ([]types.Pixel, int, int, error) {
+	// The size is checked before decoding so that a small file can't allocate a huge image
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, 0, 0, types.ErrInvalidImage
+	}
+
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > config.ImportMaxSourceSize || cfg.Height > config.ImportMaxSourceSize {
+		return nil, 0, 0, types.ErrImageTooLarge
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, 0, err
+	}
+
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, 0, 0, types.ErrInvalidImage
+	}
+
+	bounds := img.Bounds()
+	pixels := make([]types.Pixel, 0, bounds.Dx()*bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A == 0 {
+				pixels = append(pixels, types.Pixel{})
+				continue
+			}
+
+			pixels = append(pixels, types.Pixel{R: c.R, G: c.G, B: c.B, A: c.A})
+		}
+	}
+
+	return pixels, bounds.Dx(), bounds.Dy(), nil
+}
+
+// ResizeImage resamples the image to the target size.
+// The box filter averages every source pixel covered by a target pixel, weighted by alpha so that transparent pixels don't darken the edges.
+func (s *CanvasService) ResizeImage(pixels []types.Pixel, srcW, srcH, dstW, dstH int, filter ResizeFilter) []types.Pixel {
+	if srcW == dstW && srcH == dstH {
+		return pixels
+	}
+
+	resized := make([]types.Pixel, dstW*dstH)
+	for y := range dstH {
+		for x := range dstW {
+			if filter != BoxFilter {
+				sx, sy := (2*x+1)*srcW/(2*dstW), (2*y+1)*srcH/(2*dstH)
+				resized[y*dstW+x] = pixels[sy*srcW+sx]
+				continue
+			}
+
+			x0, y0 := x*srcW/dstW, y*srcH/dstH
+			x1, y1 := max((x+1)*srcW/dstW, x0+1), max((y+1)*srcH/dstH, y0+1)
+
+			var r, g, b, a, count int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					p := pixels[sy*srcW+sx]
+					r += int(p.R) * int(p.A)
+					g += int(p.G) * int(p.A)
+					b += int(p.B) * int(p.A)
+					a += int(p.A)
+					count++
+				}
+			}
+
+			if a == 0 {
+				continue
+			}
+
+			resized[y*dstW+x] = types.Pixel{
+				R: uint8((r + a/2) / a),
+				G: uint8((g + a/2) / a),
+				B: uint8((b + a/2) / a),
+				A: uint8((a + count/2) / count),
+			}
+		}
+	}
+
+	return resized
+}
+
+// colorCount is a distinct color of an image and the number of pixels that use it.
+type colorCount struct {
+	color types.Pixel
+	count int
+}
+
+// QuantizeColors reduces the image to at most n colors using median cut, fully transparent pixels are kept as they are.
+// The colors are repeatedly split in two at the median of the channel with the widest range, each group is replaced by its average color.
+func (s *CanvasService) QuantizeColors(pixels []types.Pixel, n int) []types.Pixel {
+	counts := make(map[types.Pixel]int)
+	for _, p := range pixels {
+		if p.A != 0 {
+			counts[p]++
+		}
+	}
+
+	if len(counts) <= n {
+		return pixels
+	}
+
+	colors := make([]colorCount, 0, len(counts))
+	for c, count := range counts {
+		colors = append(colors, colorCount{c, count})
+	}
+
+	boxes := [][]colorCount{colors}
+	for len(boxes) < n {
+		// Split the box with the widest channel range, boxes with a single color can't be split
+		widest, widestCh, widestRange := -1, 0, -1
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+
+			ch, r := widestChannel(box)
+			if r > widestRange {
+				widest, widestCh, widestRange = i, ch, r
+			}
+		}
+
+		if widest < 0 {
+			break
+		}
+
+		low, high := splitBox(boxes[widest], widestCh)
+		boxes[widest] = low
+		boxes = append(boxes, high)
+	}
+
+	mapped := make(map[types.Pixel]types.Pixel, len(counts))
+	for _, box := range boxes {
+		average := averageColor(box)
+		for _, c := range box {
+			mapped[c.color] = average
+		}
+	}
+
+	quantized := make([]types.Pixel, len(pixels))
+	for i, p := range pixels {
+		if p.A != 0 {
+			quantized[i] = mapped[p]
+		}
+	}
+
+	return quantized
+}
+
+// channel returns the value of the R, G, B or A channel of the color.
+func channel(p types.Pixel, i int) uint8 {
+	return [4]uint8{p.R, p.G, p.B, p.A}[i]
+}
+
+// widestChannel returns the channel with the widest range of values in the box along with that range.
+func widestChannel(box []colorCount) (int, int) {
+	widest, widestRange := 0, -1
+	for i := range 4 {
+		lo, hi := uint8(255), uint8(0)
+		for _, c := range box {
+			lo, hi = min(lo, channel(c.color, i)), max(hi, channel(c.color, i))
+		}
+
+		if r := int(hi) - int(lo); r > widestRange {
+			widest, widestRange = i, r
+		}
+	}
+
+	return widest, widestRange
+}
+
+// splitBox splits the colors of the box in two halves with about the same number of pixels, ordered by the channel.
+func splitBox(box []colorCount, ch int) ([]colorCount, []colorCount) {
+	slices.SortFunc(box, func(a, b colorCount) int { return cmp.Compare(channel(a.color, ch), channel(b.color, ch)) })
+
+	total := 0
+	for _, c := range box {
+		total += c.count
+	}
+
+	// Both halves must keep at least one color
+	split, seen := 1, box[0].count
+	for split < len(box)-1 && seen < total/2 {
+		seen += box[split].count
+		split++
+	}
+
+	return box[:split:split], box[split:]
+}
+
+// averageColor returns the average color of the pixels in the box.
+func averageColor(box []colorCount) types.Pixel {
+	var r, g, b, a, total int
+	for _, c := range box {
+		r += int(c.color.R) * c.count
+		g += int(c.color.G) * c.count
+		b += int(c.color.B) * c.count
+		a += int(c.color.A) * c.count
+		total += c.count
+	}
+
+	return types.Pixel{
+		R: uint8((r + total/2) / total),
+		G: uint8((g + total/2) / total),
+		B: uint8((b + total/2) / total),
+		A: uint8((a + total/2) / total),
+	}
+}
+
+// ImportImage writes the image into the stored layer with its top left corner at (x, y), used when the canvas is not loaded in a room.
+// Pixels outside of the canvas are dropped and canvases with a locked palette get the closest palette colors.
+func (s *CanvasService) ImportImage(canvasID, layerID, userID string, x, y, width, height int, pixels []types.Pixel) error {
+	canvas, err := s.queries.GetCanvas(canvasID)
+	if err != nil {
+		return err
+	}
+
+	layers, err := s.LoadLayers(canvasID, canvas.Width, canvas.Height)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(layers, func(layer types.LoadedLayer) bool { return layer.ID == layerID })
+	if i < 0 {
+		return types.ErrLayerNotFound
+	}
+
+	layer := &layers[i]
+	if layer.Locked {
+		return types.ErrLayerLocked
+	}
+
+	edits := make(PixelEdits)
+	tiles := make(map[[2]int]struct{})
+	now := time.Now()
+	for py := range height {
+		for px := range width {
+			cx, cy := x+px, y+py
+			if cx < 0 || cy < 0 || cx >= int(canvas.Width) || cy >= int(canvas.Height) {
+				continue
+			}
+
+			pixel := pixels[py*width+px]
+			if canvas.PaletteLocked && pixel.A != 0 {
+				pixel = canvas.Palette.Nearest(pixel)
+			}
+
+			index := cy*int(canvas.Width) + cx
+			if layer.PixelData[index] == pixel {
+				continue
+			}
+
+			layer.PixelData[index] = pixel
+			edits[index] = now
+			tiles[[2]int{cx / TileSize, cy / TileSize}] = struct{}{}
+		}
+	}
+
+	if len(edits) == 0 {
+		return nil
+	}
+
+	flattened := s.FlattenLayers(canvas.Width, canvas.Height, layers)
+
+	var layerTiles, canvasTiles []types.LoadedTile
+	for tile := range tiles {
+		layerTiles = append(layerTiles, types.LoadedTile{
+			LayerID:   layerID,
+			X:         tile[0],
+			Y:         tile[1],
+			PixelData: ExtractTile(layer.PixelData, canvas.Width, canvas.Height, tile[0], tile[1]),
+		})
+		canvasTiles = append(canvasTiles, types.LoadedTile{
+			X:         tile[0],
+			Y:         tile[1],
+			PixelData: ExtractTile(flattened, canvas.Width, canvas.Height, tile[0], tile[1]),
+		})
+	}
+
+	if err := s.SaveTiles(canvasID, layerTiles, canvasTiles); err != nil {
+		return err
+	}
+
+	if err := s.SaveEdits(canvasID, map[string]PixelEdits{userID: edits}); err != nil {
+		return err
+	}
+
+	indexes := slices.Sorted(maps.Keys(edits))
+	changed := make([]types.Pixel, len(indexes))
+	for i, index := range indexes {
+		changed[i] = flattened[index]
+	}
+
+	return s.SaveChanges(canvasID, []CanvasChange{{Indexes: indexes, Pixels: changed, Time: now}})
+}
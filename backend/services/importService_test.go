@@ -0,0 +1,228 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/CDavidSV/Pixio/types"
+)
+
+func TestResizeImage(t *testing.T) {
+	red := types.Pixel{R: 255, A: 255}
+	blue := types.Pixel{B: 255, A: 255}
+	green := types.Pixel{G: 255, A: 255}
+	white := types.Pixel{R: 255, G: 255, B: 255, A: 255}
+
+	tests := []struct {
+		name       string
+		pixels     []types.Pixel
+		srcW, srcH int
+		dstW, dstH int
+		filter     ResizeFilter
+		want       []types.Pixel
+	}{
+		{
+			name:   "same size",
+			pixels: []types.Pixel{red, blue},
+			srcW:   2, srcH: 1, dstW: 2, dstH: 1,
+			filter: BoxFilter,
+			want:   []types.Pixel{red, blue},
+		},
+		{
+			name:   "nearest enlarge",
+			pixels: []types.Pixel{red, blue},
+			srcW:   2, srcH: 1, dstW: 4, dstH: 2,
+			filter: NearestFilter,
+			want:   []types.Pixel{red, red, blue, blue, red, red, blue, blue},
+		},
+		{
+			name:   "nearest shrink samples the pixel centers",
+			pixels: []types.Pixel{red, blue, green, white},
+			srcW:   4, srcH: 1, dstW: 2, dstH: 1,
+			filter: NearestFilter,
+			want:   []types.Pixel{blue, white},
+		},
+		{
+			name:   "box shrink averages the covered pixels",
+			pixels: []types.Pixel{red, blue, red, blue},
+			srcW:   2, srcH: 2, dstW: 1, dstH: 1,
+			filter: BoxFilter,
+			want:   []types.Pixel{{R: 128, B: 128, A: 255}},
+		},
+		{
+			name:   "box shrink doesn't darken transparent edges",
+			pixels: []types.Pixel{red, {}},
+			srcW:   2, srcH: 1, dstW: 1, dstH: 1,
+			filter: BoxFilter,
+			want:   []types.Pixel{{R: 255, A: 128}},
+		},
+		{
+			name:   "box shrink of transparent pixels",
+			pixels: []types.Pixel{{R: 255}, {B: 255}},
+			srcW:   2, srcH: 1, dstW: 1, dstH: 1,
+			filter: BoxFilter,
+			want:   []types.Pixel{{}},
+		},
+		{
+			name:   "box enlarge repeats pixels",
+			pixels: []types.Pixel{red, blue},
+			srcW:   2, srcH: 1, dstW: 4, dstH: 1,
+			filter: BoxFilter,
+			want:   []types.Pixel{red, red, blue, blue},
+		},
+	}
+
+	s := &CanvasService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.ResizeImage(tt.pixels, tt.srcW, tt.srcH, tt.dstW, tt.dstH, tt.filter)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ResizeImage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuantizeColors(t *testing.T) {
+	black := types.Pixel{A: 255}
+	gray := types.Pixel{R: 10, G: 10, B: 10, A: 255}
+	white := types.Pixel{R: 255, G: 255, B: 255, A: 255}
+	offWhite := types.Pixel{R: 250, G: 250, B: 250, A: 255}
+
+	tests := []struct {
+		name   string
+		pixels []types.Pixel
+		n      int
+		want   []types.Pixel
+	}{
+		{
+			name:   "already within the limit",
+			pixels: []types.Pixel{black, white, black},
+			n:      2,
+			want:   []types.Pixel{black, white, black},
+		},
+		{
+			name:   "transparent pixels don't count as colors",
+			pixels: []types.Pixel{black, {}, white, {R: 255}},
+			n:      2,
+			want:   []types.Pixel{black, {}, white, {R: 255}},
+		},
+		{
+			name:   "similar colors are merged",
+			pixels: []types.Pixel{black, gray, white, offWhite},
+			n:      2,
+			want: []types.Pixel{
+				{R: 5, G: 5, B: 5, A: 255},
+				{R: 5, G: 5, B: 5, A: 255},
+				{R: 253, G: 253, B: 253, A: 255},
+				{R: 253, G: 253, B: 253, A: 255},
+			},
+		},
+		{
+			name:   "colors are split at the median pixel",
+			pixels: []types.Pixel{black, black, black, gray, white},
+			n:      2,
+			want: []types.Pixel{
+				black,
+				black,
+				black,
+				{R: 133, G: 133, B: 133, A: 255},
+				{R: 133, G: 133, B: 133, A: 255},
+			},
+		},
+		{
+			name:   "averages are weighted by pixel count",
+			pixels: []types.Pixel{gray, gray, gray, black, white},
+			n:      1,
+			want: []types.Pixel{
+				{R: 57, G: 57, B: 57, A: 255},
+				{R: 57, G: 57, B: 57, A: 255},
+				{R: 57, G: 57, B: 57, A: 255},
+				{R: 57, G: 57, B: 57, A: 255},
+				{R: 57, G: 57, B: 57, A: 255},
+			},
+		},
+		{
+			name:   "fully transparent pixels are kept",
+			pixels: []types.Pixel{black, {}, gray, {}, white},
+			n:      1,
+			want: []types.Pixel{
+				{R: 88, G: 88, B: 88, A: 255},
+				{},
+				{R: 88, G: 88, B: 88, A: 255},
+				{},
+				{R: 88, G: 88, B: 88, A: 255},
+			},
+		},
+	}
+
+	s := &CanvasService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.QuantizeColors(tt.pixels, tt.n)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("QuantizeColors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitBox(t *testing.T) {
+	red := func(r uint8, count int) colorCount {
+		return colorCount{color: types.Pixel{R: r, A: 255}, count: count}
+	}
+
+	tests := []struct {
+		name     string
+		box      []colorCount
+		ch       int
+		wantLow  []colorCount
+		wantHigh []colorCount
+	}{
+		{
+			name:     "two colors",
+			box:      []colorCount{red(20, 1), red(10, 1)},
+			wantLow:  []colorCount{red(10, 1)},
+			wantHigh: []colorCount{red(20, 1)},
+		},
+		{
+			name:     "even counts",
+			box:      []colorCount{red(40, 1), red(10, 1), red(30, 1), red(20, 1)},
+			wantLow:  []colorCount{red(10, 1), red(20, 1)},
+			wantHigh: []colorCount{red(30, 1), red(40, 1)},
+		},
+		{
+			name:     "split at the median pixel",
+			box:      []colorCount{red(10, 10), red(20, 1), red(30, 1)},
+			wantLow:  []colorCount{red(10, 10)},
+			wantHigh: []colorCount{red(20, 1), red(30, 1)},
+		},
+		{
+			name:     "high half keeps a color",
+			box:      []colorCount{red(10, 1), red(20, 1), red(30, 10)},
+			wantLow:  []colorCount{red(10, 1), red(20, 1)},
+			wantHigh: []colorCount{red(30, 10)},
+		},
+		{
+			name:     "ordered by the given channel",
+			box:      []colorCount{{types.Pixel{R: 10, G: 200}, 1}, {types.Pixel{R: 20, G: 100}, 1}},
+			ch:       1,
+			wantLow:  []colorCount{{types.Pixel{R: 20, G: 100}, 1}},
+			wantHigh: []colorCount{{types.Pixel{R: 10, G: 200}, 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			low, high := splitBox(tt.box, tt.ch)
+			if !slices.Equal(low, tt.wantLow) || !slices.Equal(high, tt.wantHigh) {
+				t.Fatalf("splitBox() = %v, %v, want %v, %v", low, high, tt.wantLow, tt.wantHigh)
+			}
+
+			// Both halves share the box, appending to the low half must not overwrite the high half
+			if cap(low) != len(low) {
+				t.Errorf("low half has capacity %d, want %d", cap(low), len(low))
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// DefaultLayerName is the name of the layer created for canvases that don't have any layers yet.
+const DefaultLayerName = "Layer 1"
+
+// GetLayers returns the layers of the canvas ordered from bottom to top.
+// Canvases created before layers existed get a default layer holding the pixel data stored in the canvas.
+func (s *CanvasService) GetLayers(canvasID string) ([]types.Layer, error) {
+	layers, err := s.queries.GetLayers(canvasID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(layers) > 0 {
+		return layers, nil
+	}
+
+	canvas, err := s.queries.GetCanvas(canvasID)
+	if err != nil {
+		return nil, err
+	}
+
+	pixelData, err := s.LoadCanvas(canvas.PixelData)
+	if err != nil {
+		return nil, err
+	}
+
+	tiles, err := s.SplitTiles(pixelData, canvas.Width, canvas.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	// A room load and a REST request may both find no layers, only one of them creates the default layer
+	if err := s.queries.CreateDefaultLayer(canvasID, DefaultLayerName, tiles); err != nil {
+		return nil, err
+	}
+
+	return s.queries.GetLayers(canvasID)
+}
+
+// LoadLayers returns the decompressed layers of the canvas ordered from bottom to top.
+func (s *CanvasService) LoadLayers(canvasID string, width, height uint16) ([]types.LoadedLayer, error) {
+	layers, err := s.GetLayers(canvasID)
+	if err != nil {
+		return nil, err
+	}
+
+	tiles, err := s.queries.GetLayerTiles(canvasID)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make([]types.LoadedLayer, len(layers))
+	indexes := make(map[string]int, len(layers))
+	for i, layer := range layers {
+		indexes[layer.ID] = i
+		loaded[i] = types.LoadedLayer{
+			ID:        layer.ID,
+			CanvasID:  layer.CanvasID,
+			Name:      layer.Name,
+			Position:  layer.Position,
+			Visible:   layer.Visible,
+			Opacity:   layer.Opacity,
+			Locked:    layer.Locked,
+			PixelData: make([]types.Pixel, int(width)*int(height)),
+			CreatedAt: layer.CreatedAt,
+		}
+	}
+
+	for _, tile := range tiles {
+		i, ok := indexes[tile.LayerID]
+		if !ok {
+			continue
+		}
+
+		pixelData, err := s.LoadCanvas(tile.PixelData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tile (%d, %d) of layer %s: %w", tile.X, tile.Y, tile.LayerID, err)
+		}
+
+		if err := PlaceTile(loaded[i].PixelData, width, height, tile.X, tile.Y, pixelData); err != nil {
+			return nil, fmt.Errorf("layer %s: %w", tile.LayerID, err)
+		}
+	}
+
+	return loaded, nil
+}
+
+// CreateLayer adds a new transparent layer on top of the canvas.
+func (s *CanvasService) CreateLayer(canvasID, name string) (types.Layer, error) {
+	// Make sure the existing pixel data is moved to a layer before adding a new one
+	if _, err := s.GetLayers(canvasID); err != nil {
+		return types.Layer{}, err
+	}
+
+	return s.queries.CreateLayer(canvasID, name, nil)
+}
+
+// ReorderLayers sets the order of the canvas layers, from bottom to top.
+// The given IDs must contain every layer of the canvas exactly once.
+func (s *CanvasService) ReorderLayers(canvasID string, layerIDs []string) error {
+	layers, err := s.GetLayers(canvasID)
+	if err != nil {
+		return err
+	}
+
+	if len(layerIDs) != len(layers) {
+		return types.ErrInvalidLayerOrder
+	}
+
+	seen := make(map[string]bool, len(layerIDs))
+	for _, id := range layerIDs {
+		seen[id] = true
+	}
+
+	for _, layer := range layers {
+		if !seen[layer.ID] {
+			return types.ErrInvalidLayerOrder
+		}
+	}
+
+	return s.queries.ReorderLayers(canvasID, layerIDs)
+}
+
+// DeleteLayer deletes the layer from the canvas, the last layer of a canvas can't be deleted.
+func (s *CanvasService) DeleteLayer(canvasID, layerID string) error {
+	layers, err := s.GetLayers(canvasID)
+	if err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(layers, func(l types.Layer) bool { return l.ID == layerID }) {
+		return types.ErrLayerNotFound
+	}
+
+	if len(layers) == 1 {
+		return types.ErrLastLayer
+	}
+
+	return s.queries.DeleteLayer(canvasID, layerID)
+}
+
+// FlattenLayers composites the visible layers, ordered from bottom to top, into a single image.
+func (s *CanvasService) FlattenLayers(width, height uint16, layers []types.LoadedLayer) []types.Pixel {
+	flattened := make([]types.Pixel, int(width)*int(height))
+
+	for _, layer := range layers {
+		if !layer.Visible || layer.Opacity == 0 {
+			continue
+		}
+
+		opacity := float64(layer.Opacity) / 100
+		for i := range min(len(flattened), len(layer.PixelData)) {
+			flattened[i] = blendPixel(flattened[i], layer.PixelData[i], opacity)
+		}
+	}
+
+	return flattened
+}
+
+// FlattenPixel composites the pixel at the index of every layer, the same way FlattenLayers does for the whole canvas.
+func FlattenPixel(layers []*types.LoadedLayer, index int) types.Pixel {
+	var flattened types.Pixel
+	for _, layer := range layers {
+		if !layer.Visible || layer.Opacity == 0 || index >= len(layer.PixelData) {
+			continue
+		}
+
+		flattened = blendPixel(flattened, layer.PixelData[index], float64(layer.Opacity)/100)
+	}
+
+	return flattened
+}
+
+// blendPixel draws src over dst using straight alpha.
+func blendPixel(dst, src types.Pixel, opacity float64) types.Pixel {
+	srcA := float64(src.A) / 255 * opacity
+	if srcA == 0 {
+		return dst
+	}
+
+	dstA := float64(dst.A) / 255
+	outA := srcA + dstA*(1-srcA)
+
+	blend := func(s, d uint8) uint8 {
+		return uint8((float64(s)*srcA+float64(d)*dstA*(1-srcA))/outA + 0.5)
+	}
+
+	return types.Pixel{
+		R: blend(src.R, dst.R),
+		G: blend(src.G, dst.G),
+		B: blend(src.B, dst.B),
+		A: uint8(outA*255 + 0.5),
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/CDavidSV/Pixio/types"
+)
+
+func TestBlendPixel(t *testing.T) {
+	red := types.Pixel{R: 255, A: 255}
+	blue := types.Pixel{B: 255, A: 255}
+
+	tests := []struct {
+		name    string
+		dst     types.Pixel
+		src     types.Pixel
+		opacity float64
+		want    types.Pixel
+	}{
+		{name: "opaque over opaque", dst: red, src: blue, opacity: 1, want: blue},
+		{name: "transparent source", dst: red, src: types.Pixel{G: 255}, opacity: 1, want: red},
+		{name: "zero opacity", dst: red, src: blue, opacity: 0, want: red},
+		{name: "opaque over transparent", dst: types.Pixel{}, src: blue, opacity: 1, want: blue},
+		{name: "half opacity over opaque", dst: red, src: blue, opacity: 0.5, want: types.Pixel{R: 128, B: 128, A: 255}},
+		{name: "half alpha over opaque", dst: red, src: types.Pixel{B: 255, A: 128}, opacity: 1, want: types.Pixel{R: 127, B: 128, A: 255}},
+		{name: "half alpha over transparent keeps the color", dst: types.Pixel{}, src: types.Pixel{B: 255, A: 128}, opacity: 1, want: types.Pixel{B: 255, A: 128}},
+		{name: "half alpha over half alpha", dst: types.Pixel{R: 255, A: 128}, src: types.Pixel{B: 255, A: 128}, opacity: 1, want: types.Pixel{R: 85, B: 170, A: 192}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := blendPixel(tt.dst, tt.src, tt.opacity); got != tt.want {
+				t.Errorf("blendPixel(%v, %v, %v) = %v, want %v", tt.dst, tt.src, tt.opacity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlattenLayers(t *testing.T) {
+	red := types.Pixel{R: 255, A: 255}
+	blue := types.Pixel{B: 255, A: 255}
+	transparent := types.Pixel{}
+
+	tests := []struct {
+		name   string
+		layers []types.LoadedLayer
+		want   []types.Pixel
+	}{
+		{
+			name: "no layers",
+			want: []types.Pixel{transparent, transparent},
+		},
+		{
+			name: "top layer covers the bottom one",
+			layers: []types.LoadedLayer{
+				{Visible: true, Opacity: 100, PixelData: []types.Pixel{red, red}},
+				{Visible: true, Opacity: 100, PixelData: []types.Pixel{blue, transparent}},
+			},
+			want: []types.Pixel{blue, red},
+		},
+		{
+			name: "hidden layer is skipped",
+			layers: []types.LoadedLayer{
+				{Visible: true, Opacity: 100, PixelData: []types.Pixel{red, red}},
+				{Visible: false, Opacity: 100, PixelData: []types.Pixel{blue, blue}},
+			},
+			want: []types.Pixel{red, red},
+		},
+		{
+			name: "transparent layer is skipped",
+			layers: []types.LoadedLayer{
+				{Visible: true, Opacity: 0, PixelData: []types.Pixel{red, red}},
+			},
+			want: []types.Pixel{transparent, transparent},
+		},
+		{
+			name: "layer opacity",
+			layers: []types.LoadedLayer{
+				{Visible: true, Opacity: 100, PixelData: []types.Pixel{red, red}},
+				{Visible: true, Opacity: 50, PixelData: []types.Pixel{blue, transparent}},
+			},
+			want: []types.Pixel{{R: 128, B: 128, A: 255}, red},
+		},
+		{
+			name: "short layer",
+			layers: []types.LoadedLayer{
+				{Visible: true, Opacity: 100, PixelData: []types.Pixel{red}},
+			},
+			want: []types.Pixel{red, transparent},
+		},
+	}
+
+	s := &CanvasService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.FlattenLayers(2, 1, tt.layers)
+			if len(got) != len(tt.want) {
+				t.Fatalf("FlattenLayers() returned %d pixels, want %d", len(got), len(tt.want))
+			}
+
+			layers := make([]*types.LoadedLayer, len(tt.layers))
+			for i := range tt.layers {
+				layers[i] = &tt.layers[i]
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("pixel %d = %v, want %v", i, got[i], tt.want[i])
+				}
+
+				// Rooms flatten single pixels, which must match the flattened canvas
+				if p := FlattenPixel(layers, i); p != got[i] {
+					t.Errorf("FlattenPixel(%d) = %v, FlattenLayers() has %v", i, p, got[i])
+				}
+			}
+		})
+	}
+}
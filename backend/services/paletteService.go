@@ -0,0 +1,60 @@
+package services
+
+import (
+	"slices"
+
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// PaletteEdit changes a palette and returns the resulting palette.
+type PaletteEdit func(palette types.Palette) (types.Palette, error)
+
+// AddColor appends the color to the end of the palette.
+func AddColor(color types.Pixel) PaletteEdit {
+	return func(palette types.Palette) (types.Palette, error) {
+		if len(palette) >= types.MaxPaletteColors {
+			return nil, types.ErrPaletteFull
+		}
+
+		return append(palette, color), nil
+	}
+}
+
+// UpdateColor replaces the color at the given index of the palette.
+func UpdateColor(index int, color types.Pixel) PaletteEdit {
+	return func(palette types.Palette) (types.Palette, error) {
+		if index < 0 || index >= len(palette) {
+			return nil, types.ErrInvalidColorIndex
+		}
+
+		palette[index] = color
+		return palette, nil
+	}
+}
+
+// RemoveColor removes the color at the given index, the colors after it are shifted down.
+func RemoveColor(index int) PaletteEdit {
+	return func(palette types.Palette) (types.Palette, error) {
+		if index < 0 || index >= len(palette) {
+			return nil, types.ErrInvalidColorIndex
+		}
+
+		return slices.Delete(palette, index, index+1), nil
+	}
+}
+
+// ReplaceColors replaces every color of the palette.
+func ReplaceColors(colors types.Palette) PaletteEdit {
+	return func(_ types.Palette) (types.Palette, error) {
+		if len(colors) > types.MaxPaletteColors {
+			return nil, types.ErrPaletteFull
+		}
+
+		return slices.Clone(colors), nil
+	}
+}
+
+// EditPalette applies the edit to the palette of the canvas and returns the stored palette.
+func (s *CanvasService) EditPalette(canvasID string, edit PaletteEdit) (types.Palette, error) {
+	return s.queries.EditPalette(canvasID, edit)
+}
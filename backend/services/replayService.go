@@ -0,0 +1,311 @@
+package services
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"io"
+	"time"
+
+	"github.com/CDavidSV/Pixio/config"
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// CanvasChange is an entry of the operation log of a canvas.
+// A keyframe holds every pixel of the flattened canvas, otherwise it holds the new flattened value of the changed pixels.
+type CanvasChange struct {
+	Keyframe bool
+	Indexes  []int
+	Pixels   []types.Pixel
+	Time     time.Time
+}
+
+// TimelapseOptions controls how a timelapse is rendered.
+type TimelapseOptions struct {
+	Interval   time.Duration // Canvas time between frames, zero picks an interval that fits config.TimelapseMaxFrames
+	FPS        int
+	Scale      int
+	Background types.Pixel // Drawn behind the canvas, GIF frames can't erase pixels so the timelapse is always opaque
+}
+
+// encodeChange compresses the changed pixels of an operation.
+// Indexes are stored as deltas from the previous index, followed by the RGBA value of the pixel.
+func (s *CanvasService) encodeChange(change CanvasChange) ([]byte, error) {
+	buf := binary.AppendUvarint(nil, uint64(len(change.Indexes)))
+	previous := 0
+	for i, index := range change.Indexes {
+		p := change.Pixels[i]
+		buf = binary.AppendUvarint(buf, uint64(index-previous))
+		buf = append(buf, p.R, p.G, p.B, p.A)
+		previous = index
+	}
+
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	if _, err := w.Write(buf); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// applyOp applies a stored operation to the flattened canvas.
+func (s *CanvasService) applyOp(canvas []types.Pixel, op types.CanvasOp) error {
+	if op.Keyframe {
+		pixelData, err := s.LoadCanvas(op.Data)
+		if err != nil {
+			return err
+		}
+
+		if len(pixelData) != len(canvas) {
+			return fmt.Errorf("keyframe %d: expected %d pixels, got %d", op.Seq, len(canvas), len(pixelData))
+		}
+
+		copy(canvas, pixelData)
+		return nil
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(op.Data))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	reader := bytes.NewReader(raw)
+	count, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return err
+	}
+
+	index := 0
+	var rgba [4]byte
+	for range count {
+		delta, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return fmt.Errorf("failed to read pixel index: %w", err)
+		}
+
+		if _, err := io.ReadFull(reader, rgba[:]); err != nil {
+			return fmt.Errorf("failed to read pixel: %w", err)
+		}
+
+		index += int(delta)
+		if index < len(canvas) {
+			canvas[index] = types.Pixel{R: rgba[0], G: rgba[1], B: rgba[2], A: rgba[3]}
+		}
+	}
+
+	return nil
+}
+
+// SaveChanges appends the changes to the operation log of the canvas.
+func (s *CanvasService) SaveChanges(canvasID string, changes []CanvasChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	ops := make([]types.CanvasOp, len(changes))
+	for i, change := range changes {
+		var data []byte
+		var err error
+		if change.Keyframe {
+			data, err = s.CompressPixelData(change.Pixels)
+		} else {
+			data, err = s.encodeChange(change)
+		}
+		if err != nil {
+			return err
+		}
+
+		ops[i] = types.CanvasOp{
+			CanvasID:  canvasID,
+			Keyframe:  change.Keyframe,
+			Data:      data,
+			Timestamp: change.Time,
+		}
+	}
+
+	return s.queries.CreateCanvasOps(canvasID, ops)
+}
+
+// LogKeyframe appends the flattened canvas to the operation log, used when the whole canvas changes at once.
+func (s *CanvasService) LogKeyframe(canvasID string, flattened []types.Pixel) error {
+	return s.SaveChanges(canvasID, []CanvasChange{{Keyframe: true, Pixels: flattened, Time: time.Now()}})
+}
+
+// HasOps reports whether the canvas has an operation log.
+func (s *CanvasService) HasOps(canvasID string) (bool, error) {
+	return s.queries.HasCanvasOps(canvasID)
+}
+
+// Replay reconstructs the flattened canvas as it was at the given time.
+// The replay starts at the last keyframe before that time, or at a blank canvas if there is none.
+func (s *CanvasService) Replay(canvasID string, width, height uint16, at time.Time) ([]types.Pixel, error) {
+	canvas := make([]types.Pixel, int(width)*int(height))
+
+	var applyErr error
+	err := s.queries.ScanCanvasOps(canvasID, at, true, func(op types.CanvasOp) bool {
+		applyErr = s.applyOp(canvas, op)
+		return applyErr == nil
+	})
+	if err = errors.Join(err, applyErr); err != nil {
+		return nil, err
+	}
+
+	return canvas, nil
+}
+
+// Timelapse renders the operation log of the canvas as an animated GIF, one frame per interval of canvas time.
+// Frames only redraw the area that changed since the previous frame, intervals without changes are skipped.
+func (s *CanvasService) Timelapse(canvasID string, width, height uint16, opts TimelapseOptions) (*gif.GIF, error) {
+	first, last, err := s.queries.GetCanvasOpsSpan(canvasID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Longer histories use longer intervals so that the number of frames stays within the limit
+	minInterval := max(last.Sub(first)/time.Duration(config.TimelapseMaxFrames), time.Millisecond)
+	interval := max(opts.Interval, minInterval)
+
+	w, h := int(width), int(height)
+	canvas := make([]types.Pixel, w*h)
+	shown := make([]types.Pixel, w*h)
+	anim := &gif.GIF{
+		Config: image.Config{Width: w * opts.Scale, Height: h * opts.Scale},
+	}
+	delay := max(100/opts.FPS, 1)
+
+	changed := true // The first frame is always drawn so that a canvas without changes still has an image
+	addFrame := func() {
+		if !changed {
+			return
+		}
+		changed = false
+
+		bounds := image.Rect(0, 0, w, h)
+		if len(anim.Image) > 0 {
+			var ok bool
+			if bounds, ok = diffBounds(shown, canvas, w, h); !ok {
+				return
+			}
+		}
+
+		anim.Image = append(anim.Image, s.timelapseFrame(canvas, w, bounds, opts))
+		anim.Delay = append(anim.Delay, delay)
+		anim.Disposal = append(anim.Disposal, gif.DisposalNone)
+		copy(shown, canvas)
+	}
+
+	frameEnd := first.Add(interval)
+	var applyErr error
+	err = s.queries.ScanCanvasOps(canvasID, last, false, func(op types.CanvasOp) bool {
+		for !op.Timestamp.Before(frameEnd) {
+			addFrame()
+			frameEnd = frameEnd.Add(interval)
+		}
+
+		applyErr = s.applyOp(canvas, op)
+		changed = true
+		return applyErr == nil
+	})
+	if err = errors.Join(err, applyErr); err != nil {
+		return nil, err
+	}
+	addFrame()
+
+	// Hold the finished canvas for a moment before the animation loops
+	anim.Delay[len(anim.Delay)-1] = max(delay, 200)
+
+	return anim, nil
+}
+
+// diffBounds returns the smallest rectangle that contains every pixel that differs between both canvases.
+func diffBounds(a, b []types.Pixel, width, height int) (image.Rectangle, bool) {
+	minX, minY, maxX, maxY := width, height, -1, -1
+	for i := range a {
+		if a[i] == b[i] {
+			continue
+		}
+
+		x, y := i%width, i/width
+		minX, minY = min(minX, x), min(minY, y)
+		maxX, maxY = max(maxX, x), max(maxY, y)
+	}
+
+	if maxX < 0 {
+		return image.Rectangle{}, false
+	}
+
+	return image.Rect(minX, minY, maxX+1, maxY+1), true
+}
+
+// timelapseFrame draws the area of the canvas over the background as a scaled paletted image.
+// Frames are opaque because a transparent pixel in a GIF frame keeps the pixel of the previous frame instead of erasing it.
+func (s *CanvasService) timelapseFrame(canvas []types.Pixel, width int, bounds image.Rectangle, opts TimelapseOptions) *image.Paletted {
+	colorAt := func(x, y int) color.RGBA {
+		p := blendPixel(opts.Background, canvas[y*width+x], 1)
+		return color.RGBA{R: p.R, G: p.G, B: p.B, A: 255}
+	}
+
+	pal, indexes := framePalette(bounds, colorAt)
+
+	scaled := image.Rect(bounds.Min.X*opts.Scale, bounds.Min.Y*opts.Scale, bounds.Max.X*opts.Scale, bounds.Max.Y*opts.Scale)
+	frame := image.NewPaletted(scaled, pal)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := colorAt(x, y)
+			index, ok := indexes[c]
+			if !ok {
+				index = uint8(pal.Index(c))
+				indexes[c] = index
+			}
+
+			for sy := range opts.Scale {
+				row := frame.PixOffset(x*opts.Scale, y*opts.Scale+sy)
+				for sx := range opts.Scale {
+					frame.Pix[row+sx] = index
+				}
+			}
+		}
+	}
+
+	return frame
+}
+
+// framePalette returns the colors of the area along with the index of each one.
+// Areas with more than 256 colors use the Plan 9 palette, in which case the returned indexes are empty.
+func framePalette(bounds image.Rectangle, colorAt func(x, y int) color.RGBA) (color.Palette, map[color.RGBA]uint8) {
+	indexes := make(map[color.RGBA]uint8)
+	var pal color.Palette
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := colorAt(x, y)
+			if _, ok := indexes[c]; ok {
+				continue
+			}
+
+			if len(pal) == 256 {
+				return palette.Plan9, make(map[color.RGBA]uint8)
+			}
+
+			indexes[c] = uint8(len(pal))
+			pal = append(pal, c)
+		}
+	}
+
+	return pal, indexes
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// changeOp encodes the changed pixels as a stored operation.
+func changeOp(t *testing.T, s *CanvasService, indexes []int, pixels []types.Pixel) types.CanvasOp {
+	data, err := s.encodeChange(CanvasChange{Indexes: indexes, Pixels: pixels})
+	if err != nil {
+		t.Fatalf("encodeChange() error = %v", err)
+	}
+
+	return types.CanvasOp{Data: data}
+}
+
+// keyframeOp encodes the whole canvas as a stored keyframe.
+func keyframeOp(t *testing.T, s *CanvasService, pixels []types.Pixel) types.CanvasOp {
+	data, err := s.CompressPixelData(pixels)
+	if err != nil {
+		t.Fatalf("CompressPixelData() error = %v", err)
+	}
+
+	return types.CanvasOp{Keyframe: true, Data: data}
+}
+
+func TestApplyOps(t *testing.T) {
+	red := types.Pixel{R: 255, A: 255}
+	blue := types.Pixel{B: 255, A: 255}
+	transparent := types.Pixel{}
+	s := &CanvasService{}
+
+	tests := []struct {
+		name    string
+		ops     []types.CanvasOp
+		want    []types.Pixel
+		wantErr bool
+	}{
+		{
+			name: "no operations",
+			want: []types.Pixel{transparent, transparent, transparent, transparent},
+		},
+		{
+			name: "changes",
+			ops: []types.CanvasOp{
+				changeOp(t, s, []int{0, 3}, []types.Pixel{red, red}),
+				changeOp(t, s, []int{3}, []types.Pixel{blue}),
+			},
+			want: []types.Pixel{red, transparent, transparent, blue},
+		},
+		{
+			name: "keyframe replaces the canvas",
+			ops: []types.CanvasOp{
+				changeOp(t, s, []int{0}, []types.Pixel{red}),
+				keyframeOp(t, s, []types.Pixel{blue, blue, transparent, transparent}),
+				changeOp(t, s, []int{2}, []types.Pixel{red}),
+			},
+			want: []types.Pixel{blue, blue, red, transparent},
+		},
+		{
+			name: "changes outside of the canvas are ignored",
+			ops: []types.CanvasOp{
+				changeOp(t, s, []int{1, 10}, []types.Pixel{red, red}),
+			},
+			want: []types.Pixel{transparent, red, transparent, transparent},
+		},
+		{
+			name: "keyframe of another size",
+			ops: []types.CanvasOp{
+				keyframeOp(t, s, []types.Pixel{red}),
+			},
+			wantErr: true,
+		},
+		{
+			name: "corrupted change",
+			ops: []types.CanvasOp{
+				{Data: []byte{1, 2, 3}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Replay applies the operations in order the same way
+			canvas := make([]types.Pixel, 4)
+			var err error
+			for _, op := range tt.ops {
+				if err = s.applyOp(canvas, op); err != nil {
+					break
+				}
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyOp() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !slices.Equal(canvas, tt.want) {
+				t.Errorf("canvas = %v, want %v", canvas, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffBounds(t *testing.T) {
+	red := types.Pixel{R: 255, A: 255}
+
+	tests := []struct {
+		name    string
+		changed [][2]int
+		want    image.Rectangle
+		wantOK  bool
+	}{
+		{name: "no changes"},
+		{name: "single pixel", changed: [][2]int{{2, 1}}, want: image.Rect(2, 1, 3, 2), wantOK: true},
+		{name: "opposite corners", changed: [][2]int{{3, 0}, {0, 2}}, want: image.Rect(0, 0, 4, 3), wantOK: true},
+		{name: "row", changed: [][2]int{{1, 2}, {2, 2}}, want: image.Rect(1, 2, 3, 3), wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := make([]types.Pixel, 4*3)
+			b := make([]types.Pixel, 4*3)
+			for _, p := range tt.changed {
+				b[p[1]*4+p[0]] = red
+			}
+
+			got, ok := diffBounds(a, b, 4, 3)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("diffBounds() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
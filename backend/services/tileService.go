@@ -0,0 +1,221 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/jackc/pgx/v5"
+)
+
+// TileSize is the width and height in pixels of the tiles canvases are stored in.
+const TileSize = 64
+
+// TileGrid returns the number of tile columns and rows of a canvas.
+func TileGrid(width, height uint16) (cols, rows int) {
+	return (int(width) + TileSize - 1) / TileSize, (int(height) + TileSize - 1) / TileSize
+}
+
+// TileBounds returns the top left pixel and the size of the tile, tiles on the right and bottom edges may be smaller than TileSize.
+func TileBounds(width, height uint16, tileX, tileY int) (x, y, w, h int) {
+	x, y = tileX*TileSize, tileY*TileSize
+	return x, y, min(TileSize, int(width)-x), min(TileSize, int(height)-y)
+}
+
+// ValidTile reports whether the tile is part of the canvas.
+func ValidTile(width, height uint16, tileX, tileY int) bool {
+	cols, rows := TileGrid(width, height)
+	return tileX >= 0 && tileY >= 0 && tileX < cols && tileY < rows
+}
+
+// ExtractTile copies the pixels of the tile out of the canvas pixel data.
+func ExtractTile(pixelData []types.Pixel, width, height uint16, tileX, tileY int) []types.Pixel {
+	x, y, w, h := TileBounds(width, height, tileX, tileY)
+
+	tile := make([]types.Pixel, 0, w*h)
+	for row := y; row < y+h; row++ {
+		start := row*int(width) + x
+		tile = append(tile, pixelData[start:start+w]...)
+	}
+
+	return tile
+}
+
+// PlaceTile copies the pixels of the tile into the canvas pixel data.
+func PlaceTile(pixelData []types.Pixel, width, height uint16, tileX, tileY int, tile []types.Pixel) error {
+	if !ValidTile(width, height, tileX, tileY) {
+		return fmt.Errorf("tile (%d, %d) is outside of the canvas", tileX, tileY)
+	}
+
+	x, y, w, h := TileBounds(width, height, tileX, tileY)
+	if len(tile) != w*h {
+		return fmt.Errorf("tile (%d, %d): expected %d pixels, got %d", tileX, tileY, w*h, len(tile))
+	}
+
+	for row := range h {
+		copy(pixelData[(y+row)*int(width)+x:], tile[row*w:(row+1)*w])
+	}
+
+	return nil
+}
+
+// EncodeTile compresses the pixels of a tile, returning nil when every pixel is transparent.
+func (s *CanvasService) EncodeTile(pixelData []types.Pixel) ([]byte, error) {
+	blank := true
+	for _, pixel := range pixelData {
+		if pixel.A != 0 {
+			blank = false
+			break
+		}
+	}
+
+	if blank {
+		return nil, nil
+	}
+
+	return s.CompressPixelData(pixelData)
+}
+
+// SplitTiles splits the canvas pixel data into compressed tiles, leaving out fully transparent tiles.
+func (s *CanvasService) SplitTiles(pixelData []types.Pixel, width, height uint16) ([]types.Tile, error) {
+	if len(pixelData) != int(width)*int(height) {
+		return nil, fmt.Errorf("expected %d pixels, got %d", int(width)*int(height), len(pixelData))
+	}
+
+	var tiles []types.Tile
+	cols, rows := TileGrid(width, height)
+	for tileY := range rows {
+		for tileX := range cols {
+			data, err := s.EncodeTile(ExtractTile(pixelData, width, height, tileX, tileY))
+			if err != nil {
+				return nil, err
+			}
+
+			if data != nil {
+				tiles = append(tiles, types.Tile{X: tileX, Y: tileY, PixelData: data})
+			}
+		}
+	}
+
+	return tiles, nil
+}
+
+// SaveTiles stores the changed tiles of the layers along with the affected tiles of the flattened canvas.
+func (s *CanvasService) SaveTiles(canvasID string, layerTiles, canvasTiles []types.LoadedTile) error {
+	encode := func(tiles []types.LoadedTile) ([]types.Tile, error) {
+		encoded := make([]types.Tile, len(tiles))
+		for i, tile := range tiles {
+			data, err := s.EncodeTile(tile.PixelData)
+			if err != nil {
+				return nil, err
+			}
+
+			encoded[i] = types.Tile{LayerID: tile.LayerID, X: tile.X, Y: tile.Y, PixelData: data}
+		}
+
+		return encoded, nil
+	}
+
+	encodedLayerTiles, err := encode(layerTiles)
+	if err != nil {
+		return err
+	}
+
+	encodedCanvasTiles, err := encode(canvasTiles)
+	if err != nil {
+		return err
+	}
+
+	return s.queries.SaveTiles(canvasID, encodedLayerTiles, encodedCanvasTiles)
+}
+
+// GetCanvasTile returns the stored pixels of a tile of the flattened canvas.
+func (s *CanvasService) GetCanvasTile(canvasID string, width, height uint16, tileX, tileY int) ([]types.Pixel, error) {
+	_, _, w, h := TileBounds(width, height, tileX, tileY)
+
+	// Canvases that were never split in tiles are migrated together with their layers
+	if _, err := s.GetLayers(canvasID); err != nil {
+		return nil, err
+	}
+
+	tile, err := s.queries.GetCanvasTile(canvasID, tileX, tileY)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return make([]types.Pixel, w*h), nil
+		}
+
+		return nil, err
+	}
+
+	pixelData, err := s.LoadCanvas(tile.PixelData)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pixelData) != w*h {
+		return nil, fmt.Errorf("tile (%d, %d): expected %d pixels, got %d", tileX, tileY, w*h, len(pixelData))
+	}
+
+	return pixelData, nil
+}
+
+// LoadFlattenedCanvas returns the stored pixels of the whole flattened canvas.
+func (s *CanvasService) LoadFlattenedCanvas(canvasID string, width, height uint16) ([]types.Pixel, error) {
+	// Canvases that were never split in tiles are migrated together with their layers
+	if _, err := s.GetLayers(canvasID); err != nil {
+		return nil, err
+	}
+
+	tiles, err := s.queries.GetCanvasTiles(canvasID)
+	if err != nil {
+		return nil, err
+	}
+
+	flattened := make([]types.Pixel, int(width)*int(height))
+	for _, tile := range tiles {
+		pixelData, err := s.LoadCanvas(tile.PixelData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tile (%d, %d): %w", tile.X, tile.Y, err)
+		}
+
+		if err := PlaceTile(flattened, width, height, tile.X, tile.Y, pixelData); err != nil {
+			return nil, err
+		}
+	}
+
+	return flattened, nil
+}
+
+// RebuildCanvasTiles recomposes every tile of the flattened canvas from the stored layers.
+// Used when a layer changes how it is composited while the canvas is not loaded in a room.
+func (s *CanvasService) RebuildCanvasTiles(canvasID string) error {
+	canvas, err := s.queries.GetCanvas(canvasID)
+	if err != nil {
+		return err
+	}
+
+	layers, err := s.LoadLayers(canvasID, canvas.Width, canvas.Height)
+	if err != nil {
+		return err
+	}
+
+	flattened := s.FlattenLayers(canvas.Width, canvas.Height, layers)
+
+	var tiles []types.LoadedTile
+	cols, rows := TileGrid(canvas.Width, canvas.Height)
+	for tileY := range rows {
+		for tileX := range cols {
+			tiles = append(tiles, types.LoadedTile{
+				X:         tileX,
+				Y:         tileY,
+				PixelData: ExtractTile(flattened, canvas.Width, canvas.Height, tileX, tileY),
+			})
+		}
+	}
+
+	if err := s.SaveTiles(canvasID, nil, tiles); err != nil {
+		return err
+	}
+
+	return s.LogKeyframe(canvasID, flattened)
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// gradient returns pixels that are all different so that misplaced pixels are noticed.
+func gradient(width, height int) []types.Pixel {
+	pixels := make([]types.Pixel, width*height)
+	for i := range pixels {
+		pixels[i] = types.Pixel{R: uint8(i), G: uint8(i >> 8), B: uint8(i >> 16), A: 255}
+	}
+
+	return pixels
+}
+
+func TestTileBounds(t *testing.T) {
+	tests := []struct {
+		name               string
+		width, height      uint16
+		tileX, tileY       int
+		wantX, wantY       int
+		wantW, wantH       int
+		wantCols, wantRows int
+	}{
+		{name: "single tile", width: 64, height: 64, wantW: 64, wantH: 64, wantCols: 1, wantRows: 1},
+		{name: "canvas smaller than a tile", width: 10, height: 20, wantW: 10, wantH: 20, wantCols: 1, wantRows: 1},
+		{name: "inner tile", width: 130, height: 70, tileX: 1, tileY: 0, wantX: 64, wantW: 64, wantH: 64, wantCols: 3, wantRows: 2},
+		{name: "right edge tile", width: 130, height: 70, tileX: 2, tileY: 0, wantX: 128, wantW: 2, wantH: 64, wantCols: 3, wantRows: 2},
+		{name: "bottom right edge tile", width: 130, height: 70, tileX: 2, tileY: 1, wantX: 128, wantY: 64, wantW: 2, wantH: 6, wantCols: 3, wantRows: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cols, rows := TileGrid(tt.width, tt.height)
+			if cols != tt.wantCols || rows != tt.wantRows {
+				t.Errorf("TileGrid() = %d, %d, want %d, %d", cols, rows, tt.wantCols, tt.wantRows)
+			}
+
+			x, y, w, h := TileBounds(tt.width, tt.height, tt.tileX, tt.tileY)
+			if x != tt.wantX || y != tt.wantY || w != tt.wantW || h != tt.wantH {
+				t.Errorf("TileBounds() = %d, %d, %d, %d, want %d, %d, %d, %d", x, y, w, h, tt.wantX, tt.wantY, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestExtractAndPlaceTile(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height uint16
+	}{
+		{name: "exact tiles", width: 128, height: 64},
+		{name: "edge tiles", width: 130, height: 70},
+		{name: "smaller than a tile", width: 5, height: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pixels := gradient(int(tt.width), int(tt.height))
+			rebuilt := make([]types.Pixel, len(pixels))
+
+			cols, rows := TileGrid(tt.width, tt.height)
+			for tileY := range rows {
+				for tileX := range cols {
+					tile := ExtractTile(pixels, tt.width, tt.height, tileX, tileY)
+
+					_, _, w, h := TileBounds(tt.width, tt.height, tileX, tileY)
+					if len(tile) != w*h {
+						t.Fatalf("tile (%d, %d) has %d pixels, want %d", tileX, tileY, len(tile), w*h)
+					}
+
+					if err := PlaceTile(rebuilt, tt.width, tt.height, tileX, tileY, tile); err != nil {
+						t.Fatalf("PlaceTile(%d, %d) error = %v", tileX, tileY, err)
+					}
+				}
+			}
+
+			if !slices.Equal(rebuilt, pixels) {
+				t.Error("placing every extracted tile didn't rebuild the canvas")
+			}
+		})
+	}
+}
+
+func TestPlaceTileErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		tileX, tileY int
+		size         int
+	}{
+		{name: "negative tile", tileX: -1, size: 64 * 64},
+		{name: "tile past the right edge", tileX: 3, size: 64 * 64},
+		{name: "tile past the bottom edge", tileY: 2, size: 64 * 64},
+		{name: "wrong number of pixels", size: 10},
+		{name: "full tile on an edge", tileX: 2, tileY: 1, size: 64 * 64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pixels := make([]types.Pixel, 130*70)
+			if err := PlaceTile(pixels, 130, 70, tt.tileX, tt.tileY, make([]types.Pixel, tt.size)); err == nil {
+				t.Error("PlaceTile() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestSplitTiles(t *testing.T) {
+	// Only the top left and bottom right tiles have visible pixels
+	pixels := make([]types.Pixel, 130*70)
+	pixels[0] = types.Pixel{R: 255, A: 255}
+	pixels[len(pixels)-1] = types.Pixel{B: 255, A: 255}
+
+	s := &CanvasService{}
+	tiles, err := s.SplitTiles(pixels, 130, 70)
+	if err != nil {
+		t.Fatalf("SplitTiles() error = %v", err)
+	}
+
+	if len(tiles) != 2 || tiles[0].X != 0 || tiles[0].Y != 0 || tiles[1].X != 2 || tiles[1].Y != 1 {
+		t.Fatalf("SplitTiles() returned tiles %v, want (0, 0) and (2, 1)", tiles)
+	}
+
+	rebuilt := make([]types.Pixel, len(pixels))
+	for _, tile := range tiles {
+		tilePixels, err := s.LoadCanvas(tile.PixelData)
+		if err != nil {
+			t.Fatalf("LoadCanvas() error = %v", err)
+		}
+
+		if err := PlaceTile(rebuilt, 130, 70, tile.X, tile.Y, tilePixels); err != nil {
+			t.Fatalf("PlaceTile() error = %v", err)
+		}
+	}
+
+	if !slices.Equal(rebuilt, pixels) {
+		t.Error("the split tiles don't rebuild the canvas")
+	}
+
+	if _, err := s.SplitTiles(pixels[1:], 130, 70); err == nil {
+		t.Error("SplitTiles() with missing pixels error = nil, want an error")
+	}
+}
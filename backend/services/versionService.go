@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateVersion stores a snapshot of the layers as a new version of the canvas.
+// createdBy is empty for versions saved automatically.
+func (s *CanvasService) CreateVersion(canvasID string, width, height uint16, layers []types.LoadedLayer, editCount int, label, createdBy string) (types.Version, error) {
+	flattened, err := s.CompressPixelData(s.FlattenLayers(width, height, layers))
+	if err != nil {
+		return types.Version{}, err
+	}
+
+	versionLayers := make([]types.VersionLayer, len(layers))
+	for i, layer := range layers {
+		compressed, err := s.CompressPixelData(layer.PixelData)
+		if err != nil {
+			return types.Version{}, err
+		}
+
+		versionLayers[i] = types.VersionLayer{
+			ID:        layer.ID,
+			Name:      layer.Name,
+			Position:  i,
+			Visible:   layer.Visible,
+			Opacity:   layer.Opacity,
+			Locked:    layer.Locked,
+			PixelData: compressed,
+		}
+	}
+
+	return s.queries.CreateVersion(canvasID, label, createdBy, editCount, flattened, versionLayers)
+}
+
+// SaveVersion stores the layers saved in the database as a new version of the canvas.
+// Used when the canvas is not loaded in a room, otherwise the room's layers are more recent.
+func (s *CanvasService) SaveVersion(canvasID, label, createdBy string) (types.Version, error) {
+	canvas, err := s.queries.GetCanvas(canvasID)
+	if err != nil {
+		return types.Version{}, err
+	}
+
+	layers, err := s.LoadLayers(canvasID, canvas.Width, canvas.Height)
+	if err != nil {
+		return types.Version{}, err
+	}
+
+	return s.CreateVersion(canvasID, canvas.Width, canvas.Height, layers, 0, label, createdBy)
+}
+
+// RestoreVersion replaces the layers of the canvas with the layers of the version and returns them decompressed along with the flattened canvas.
+func (s *CanvasService) RestoreVersion(canvasID, versionID string) ([]types.LoadedLayer, []types.Pixel, error) {
+	canvas, err := s.queries.GetCanvas(canvasID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	version, err := s.queries.GetVersion(canvasID, versionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, types.ErrVersionNotFound
+		}
+
+		return nil, nil, err
+	}
+
+	if len(version.Layers) == 0 {
+		return nil, nil, fmt.Errorf("version %s has no layers", versionID)
+	}
+
+	loaded := make([]types.LoadedLayer, len(version.Layers))
+	layers := make([]types.Layer, len(version.Layers))
+	var layerTiles []types.Tile
+	for i, layer := range version.Layers {
+		pixelData, err := s.LoadCanvas(layer.PixelData)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load layer %s of version %s: %w", layer.ID, versionID, err)
+		}
+
+		if len(pixelData) != int(canvas.Width)*int(canvas.Height) {
+			return nil, nil, fmt.Errorf("layer %s of version %s: expected %d pixels, got %d", layer.ID, versionID, int(canvas.Width)*int(canvas.Height), len(pixelData))
+		}
+
+		layers[i] = types.Layer{
+			ID:       layer.ID,
+			CanvasID: canvasID,
+			Name:     layer.Name,
+			Position: i,
+			Visible:  layer.Visible,
+			Opacity:  layer.Opacity,
+			Locked:   layer.Locked,
+		}
+		loaded[i] = types.LoadedLayer{
+			ID:        layer.ID,
+			CanvasID:  canvasID,
+			Name:      layer.Name,
+			Position:  i,
+			Visible:   layer.Visible,
+			Opacity:   layer.Opacity,
+			Locked:    layer.Locked,
+			PixelData: pixelData,
+		}
+
+		tiles, err := s.SplitTiles(pixelData, canvas.Width, canvas.Height)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, tile := range tiles {
+			tile.LayerID = layer.ID
+			layerTiles = append(layerTiles, tile)
+		}
+	}
+
+	flattened := s.FlattenLayers(canvas.Width, canvas.Height, loaded)
+	canvasTiles, err := s.SplitTiles(flattened, canvas.Width, canvas.Height)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.queries.ReplaceLayers(canvasID, layers, layerTiles, canvasTiles); err != nil {
+		return nil, nil, err
+	}
+
+	return loaded, flattened, nil
+}
@@ -1,6 +1,9 @@
 package types
 
-import "fmt"
+import (
+	"database/sql/driver"
+	"fmt"
+)
 
 func (ns *NullString) Scan(value any) error {
 	if value == nil {
@@ -51,3 +54,33 @@ func (ot *ObjectType) Scan(value any) error {
 	}
 	return fmt.Errorf("failed to scan NullString: %v", value)
 }
+
+func (p *Palette) Scan(value any) error {
+	if value == nil {
+		*p = Palette{}
+		return nil
+	}
+
+	data, ok := value.([]byte)
+	if !ok || len(data)%4 != 0 {
+		return fmt.Errorf("failed to scan Palette: %v", value)
+	}
+
+	palette := make(Palette, len(data)/4)
+	for i := range palette {
+		palette[i] = Pixel{R: data[i*4], G: data[i*4+1], B: data[i*4+2], A: data[i*4+3]}
+	}
+
+	*p = palette
+	return nil
+}
+
+// Value stores the palette as consecutive RGBA bytes.
+func (p Palette) Value() (driver.Value, error) {
+	data := make([]byte, 0, len(p)*4)
+	for _, color := range p {
+		data = append(data, color.R, color.G, color.B, color.A)
+	}
+
+	return data, nil
+}
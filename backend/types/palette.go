@@ -0,0 +1,91 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseColor parses a color in the #RRGGBB or #RRGGBBAA format, colors without alpha are opaque.
+func ParseColor(s string) (Pixel, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 && len(s) != 8 {
+		return Pixel{}, ErrInvalidColor
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return Pixel{}, ErrInvalidColor
+	}
+
+	color := Pixel{R: b[0], G: b[1], B: b[2], A: 255}
+	if len(b) == 4 {
+		color.A = b[3]
+	}
+
+	return color, nil
+}
+
+// Hex returns the color in the #RRGGBBAA format.
+func (p Pixel) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x%02x", p.R, p.G, p.B, p.A)
+}
+
+// Contains reports whether the color is part of the palette.
+func (p Palette) Contains(color Pixel) bool {
+	for _, c := range p {
+		if c == color {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Nearest returns the palette color closest to the given color, or a transparent pixel if the palette is empty.
+func (p Palette) Nearest(color Pixel) Pixel {
+	var nearest Pixel
+	best := -1
+	for _, c := range p {
+		dr, dg, db, da := int(c.R)-int(color.R), int(c.G)-int(color.G), int(c.B)-int(color.B), int(c.A)-int(color.A)
+		if d := dr*dr + dg*dg + db*db + da*da; best < 0 || d < best {
+			nearest, best = c, d
+		}
+	}
+
+	return nearest
+}
+
+func (p Palette) MarshalJSON() ([]byte, error) {
+	colors := make([]string, len(p))
+	for i, color := range p {
+		colors[i] = color.Hex()
+	}
+
+	return json.Marshal(colors)
+}
+
+func (p *Palette) UnmarshalJSON(data []byte) error {
+	var colors []string
+	if err := json.Unmarshal(data, &colors); err != nil {
+		return err
+	}
+
+	if len(colors) > MaxPaletteColors {
+		return ErrPaletteFull
+	}
+
+	palette := make(Palette, len(colors))
+	for i, s := range colors {
+		color, err := ParseColor(s)
+		if err != nil {
+			return err
+		}
+
+		palette[i] = color
+	}
+
+	*p = palette
+	return nil
+}
@@ -11,6 +11,7 @@ type NullString string
 type AccessType int
 type AccessRole int
 type ObjectType string
+type Palette []Pixel
 
 const (
 	Restricted AccessType = iota
@@ -28,6 +29,9 @@ const (
 	CollectionObject ObjectType = "collection"
 )
 
+// MaxPaletteColors is the maximum number of colors a canvas palette can hold.
+const MaxPaletteColors = 256
+
 // Errors
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
@@ -37,6 +41,17 @@ var (
 	ErrSessionExpired     = errors.New("session expired")
 	ErrUserAccessDenied   = errors.New("user has no permissions to access the canvas")
 	ErrCanvasDoesNotExist = errors.New("canvas does not exist")
+	ErrLayerNotFound      = errors.New("layer not found")
+	ErrLastLayer          = errors.New("a canvas must have at least one layer")
+	ErrInvalidLayerOrder  = errors.New("layer order must contain every layer of the canvas exactly once")
+	ErrPaletteFull        = errors.New("palette cannot hold more colors")
+	ErrInvalidColorIndex  = errors.New("color index is out of the palette range")
+	ErrInvalidColor       = errors.New("invalid color")
+	ErrCommentNotFound    = errors.New("comment not found")
+	ErrVersionNotFound    = errors.New("version not found")
+	ErrLayerLocked        = errors.New("layer is locked")
+	ErrInvalidImage       = errors.New("image is not a valid PNG, GIF or JPEG")
+	ErrImageTooLarge      = errors.New("image is too large")
 )
 
 type ErrorResponse struct {
@@ -93,12 +108,15 @@ type Canvas struct {
 	Description    string     `json:"description"`
 	Width          uint16     `json:"width"`
 	Height         uint16     `json:"height"`
-	PixelData      []byte     `json:"pixel_data"`
+	PixelData      []byte     `json:"pixel_data"` // Stored as the initial pixels, the current pixels are in tiles and replace them when the canvas is returned
 	LastEditedAt   time.Time  `json:"last_edited_at"`
 	LinkAccessType AccessType `json:"access_type"`
 	LinkAccessRole AccessRole `json:"access_role"`
 	CreatedAt      time.Time  `json:"created_at"`
 	StarCount      uint       `json:"start_count"`
+	Palette        Palette    `json:"palette"`
+	PaletteLocked  bool       `json:"palette_locked"`
+	ViewerChat     bool       `json:"viewer_chat"`
 }
 
 type LoadedCanvas struct {
@@ -114,13 +132,200 @@ type LoadedCanvas struct {
 	LinkAccessRole AccessRole `json:"access_role"`
 	CreatedAt      time.Time  `json:"created_at"`
 	StarCount      uint       `json:"start_count"`
+	Palette        Palette    `json:"palette"`
+	PaletteLocked  bool       `json:"palette_locked"`
+	ViewerChat     bool       `json:"viewer_chat"`
 }
 
 type CreateCanvasDTO struct {
 	Title       string `json:"title" validate:"required,min=1,max=32"`
 	Description string `json:"description" validate:"max=512"`
-	Width       uint16 `json:"width" validate:"min=100,max=1024"`
-	Height      uint16 `json:"height" validate:"min=100,max=1024"`
+	Width       uint16 `json:"width" validate:"min=100,max=2048"`
+	Height      uint16 `json:"height" validate:"min=100,max=2048"`
+}
+
+type Layer struct {
+	ID        string    `json:"id"`
+	CanvasID  string    `json:"canvas_id"`
+	Name      string    `json:"name"`
+	Position  int       `json:"position"`
+	Visible   bool      `json:"visible"`
+	Opacity   uint8     `json:"opacity"`
+	Locked    bool      `json:"locked"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LoadedLayer struct {
+	ID        string    `json:"id"`
+	CanvasID  string    `json:"canvas_id"`
+	Name      string    `json:"name"`
+	Position  int       `json:"position"`
+	Visible   bool      `json:"visible"`
+	Opacity   uint8     `json:"opacity"`
+	Locked    bool      `json:"locked"`
+	PixelData []Pixel   `json:"pixel_data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Tile is a square area of a layer or of the flattened canvas, edge tiles are smaller when the canvas size is not a multiple of the tile size.
+type Tile struct {
+	LayerID   string `json:"layer_id,omitempty"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	PixelData []byte `json:"pixel_data"` // Nil when every pixel of the tile is transparent
+}
+
+type LoadedTile struct {
+	LayerID   string  `json:"layer_id,omitempty"`
+	X         int     `json:"x"`
+	Y         int     `json:"y"`
+	PixelData []Pixel `json:"pixel_data"`
+}
+
+// Version is a saved snapshot of every layer of a canvas. Pixel data is only loaded when downloading or restoring a version.
+type Version struct {
+	ID        string         `json:"id"`
+	CanvasID  string         `json:"canvas_id"`
+	Label     NullString     `json:"label"`
+	CreatedBy NullString     `json:"created_by"` // Empty for versions saved automatically
+	EditCount int            `json:"edit_count"`
+	Timestamp time.Time      `json:"timestamp"`
+	PixelData []byte         `json:"pixel_data,omitempty"` // Compressed flattened image
+	Layers    []VersionLayer `json:"layers,omitempty"`
+}
+
+type VersionLayer struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Position  int    `json:"position"`
+	Visible   bool   `json:"visible"`
+	Opacity   uint8  `json:"opacity"`
+	Locked    bool   `json:"locked"`
+	PixelData []byte `json:"pixel_data"` // Compressed layer pixels
+}
+
+// Edit is a batch of pixels changed by a user, saved against the latest version of the canvas at the time.
+type Edit struct {
+	ID         string     `json:"id"`
+	CanvasID   string     `json:"canvas_id"`
+	VersionID  NullString `json:"version_id"`
+	UserID     string     `json:"user_id"`
+	PixelCount int        `json:"pixel_count"`
+	Data       []byte     `json:"-"`         // Compressed pixel indexes and change times
+	Timestamp  time.Time  `json:"timestamp"` // Time of the most recent change in the batch
+}
+
+// CanvasOp is an entry of the operation log of a canvas, either the pixels of the flattened canvas
+// changed by an operation or a keyframe with every pixel after a change that affects the whole canvas.
+type CanvasOp struct {
+	Seq       int64     `json:"seq"`
+	CanvasID  string    `json:"canvas_id"`
+	Keyframe  bool      `json:"keyframe"`
+	Data      []byte    `json:"-"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Blame is the most recent change of a pixel or of any pixel in an area.
+type Blame struct {
+	UserID    string     `json:"user_id"`
+	Username  string     `json:"username"`
+	AvatarURL NullString `json:"avatar_url"`
+	X         int        `json:"x"`
+	Y         int        `json:"y"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+// Contribution summarizes the changes of a collaborator on a canvas.
+type Contribution struct {
+	UserID       string     `json:"user_id"`
+	Username     string     `json:"username"`
+	AvatarURL    NullString `json:"avatar_url"`
+	Pixels       int        `json:"pixels"`        // Pixels the user was the last to change
+	EditedPixels int        `json:"edited_pixels"` // Pixel changes made by the user, counting a pixel once per edit batch
+	LastEditAt   time.Time  `json:"last_edit_at"`
+}
+
+type CreateVersionDTO struct {
+	Label string `json:"label" validate:"max=64"`
+}
+
+type CreateLayerDTO struct {
+	Name string `json:"name" validate:"required,min=1,max=32"`
+}
+
+type UpdateLayerDTO struct {
+	Name    string `json:"name" validate:"required,min=1,max=32"`
+	Visible bool   `json:"visible"`
+	Opacity int    `json:"opacity" validate:"min=0,max=100"`
+	Locked  bool   `json:"locked"`
+}
+
+type ReorderLayersDTO struct {
+	LayerIDs []string `json:"layer_ids"`
+}
+
+type UpdatePaletteDTO struct {
+	Colors Palette `json:"colors"`
+}
+
+type PaletteColorDTO struct {
+	Color string `json:"color" validate:"required"`
+}
+
+type UpdatePaletteLockDTO struct {
+	Locked bool `json:"locked"`
+}
+
+type ChatMessage struct {
+	ID        string     `json:"id"`
+	CanvasID  string     `json:"canvas_id"`
+	UserID    string     `json:"user_id"`
+	Username  string     `json:"username"`
+	AvatarURL NullString `json:"avatar_url"`
+	Content   string     `json:"content"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type UpdateChatSettingsDTO struct {
+	ViewerChat bool `json:"viewer_chat"`
+}
+
+// Comment is a comment thread anchored to a point or a rectangle of a canvas, or a reply to one when ParentID is set.
+// A width and height of 0 anchor the comment to the single pixel at (X, Y). Replies share the anchor of their thread.
+type Comment struct {
+	ID         string     `json:"id"`
+	CanvasID   string     `json:"canvas_id"`
+	ParentID   NullString `json:"parent_id"`
+	UserID     string     `json:"user_id"`
+	Username   string     `json:"username"`
+	AvatarURL  NullString `json:"avatar_url"`
+	Content    string     `json:"content"`
+	X          int        `json:"x"`
+	Y          int        `json:"y"`
+	Width      int        `json:"width"`
+	Height     int        `json:"height"`
+	Resolved   bool       `json:"resolved"`
+	ResolvedBy NullString `json:"resolved_by"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Replies    []Comment  `json:"replies,omitempty"`
+}
+
+type CreateCommentDTO struct {
+	Content string `json:"content" validate:"required,min=1,max=1000"`
+	X       int    `json:"x" validate:"min=0"`
+	Y       int    `json:"y" validate:"min=0"`
+	Width   int    `json:"width" validate:"min=0"`
+	Height  int    `json:"height" validate:"min=0"`
+}
+
+type CommentContentDTO struct {
+	Content string `json:"content" validate:"required,min=1,max=1000"`
+}
+
+type ResolveCommentDTO struct {
+	Resolved bool `json:"resolved"`
 }
 
 type UserAccess struct {
@@ -158,6 +363,6 @@ type UpdateCanvasDTO struct {
 }
 
 type UpdateCanvasSizeDTO struct {
-	Width  uint16 `json:"width" validate:"min=100,max=1024"`
-	Height uint16 `json:"height" validate:"min=100,max=1024"`
+	Width  uint16 `json:"width" validate:"min=100,max=2048"`
+	Height uint16 `json:"height" validate:"min=100,max=2048"`
 }
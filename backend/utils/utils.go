@@ -20,9 +20,22 @@ const (
 
 const (
 	// 400 Bad Request
-	ErrInvalidJSONBody  ClientErrorCode = 1000
-	ErrCanvasIDRequired ClientErrorCode = 1001
-	ErrInvalidID        ClientErrorCode = 1002
+	ErrInvalidJSONBody   ClientErrorCode = 1000
+	ErrCanvasIDRequired  ClientErrorCode = 1001
+	ErrInvalidID         ClientErrorCode = 1002
+	ErrInvalidLayerOrder ClientErrorCode = 1003
+	ErrInvalidColor      ClientErrorCode = 1004
+	ErrInvalidColorIndex ClientErrorCode = 1005
+	ErrInvalidTile       ClientErrorCode = 1006
+	ErrInvalidPageLimit  ClientErrorCode = 1007
+	ErrInvalidAnchor     ClientErrorCode = 1008
+	ErrInvalidArea       ClientErrorCode = 1009
+	ErrInvalidTimestamp  ClientErrorCode = 1010
+	ErrInvalidImageScale ClientErrorCode = 1011
+	ErrInvalidTimelapse  ClientErrorCode = 1012
+	ErrGridScale         ClientErrorCode = 1013
+	ErrInvalidImage      ClientErrorCode = 1014
+	ErrInvalidImport     ClientErrorCode = 1015
 
 	// 401 Unauthorized
 	ErrInvalidCredentials         ClientErrorCode = 1100
@@ -34,20 +47,41 @@ const (
 	ErrForbiddenCanvasAccess      ClientErrorCode = 1106
 	ErrNotCanvasOwner             ClientErrorCode = 1107
 	ErrAccessRulesUpdateForbidden ClientErrorCode = 1108
+	ErrCanvasEditForbidden        ClientErrorCode = 1109
+	ErrCommentEditForbidden       ClientErrorCode = 1110
 
 	// 404 Not Found
-	ErrUserNotFound   ClientErrorCode = 1200
-	ErrCanvasNotFound ClientErrorCode = 1201
+	ErrUserNotFound    ClientErrorCode = 1200
+	ErrCanvasNotFound  ClientErrorCode = 1201
+	ErrLayerNotFound   ClientErrorCode = 1202
+	ErrCommentNotFound ClientErrorCode = 1203
+	ErrVersionNotFound ClientErrorCode = 1204
 
 	// 409 Conflict
 	ErrUserAlreadyRegistered ClientErrorCode = 1300
+	ErrLastLayer             ClientErrorCode = 1301
+	ErrPaletteFull           ClientErrorCode = 1302
+	ErrLayerLocked           ClientErrorCode = 1303
 )
 
 var clientErrorCodes = map[ClientErrorCode]string{
 	// 400 Bad Request
-	ErrInvalidJSONBody:  "Invalid JSON body",
-	ErrCanvasIDRequired: "Canvas ID must be provided",
-	ErrInvalidID:        "ID must be provided and of valid format",
+	ErrInvalidJSONBody:   "Invalid JSON body",
+	ErrCanvasIDRequired:  "Canvas ID must be provided",
+	ErrInvalidID:         "ID must be provided and of valid format",
+	ErrInvalidLayerOrder: "Layer order must contain every layer of the canvas exactly once",
+	ErrInvalidColor:      "Color must be in the #RRGGBB or #RRGGBBAA format",
+	ErrInvalidColorIndex: "Color index is out of the palette range",
+	ErrInvalidTile:       "Tile coordinates are outside of the canvas",
+	ErrInvalidPageLimit:  "Limit must be a positive number",
+	ErrInvalidAnchor:     "Comment anchor must be inside the canvas",
+	ErrInvalidArea:       "Area must be inside the canvas",
+	ErrInvalidTimestamp:  "Timestamp must be in the RFC 3339 format or a Unix time in milliseconds",
+	ErrInvalidImageScale: "Scale must be a whole number that keeps the image within the size limit",
+	ErrInvalidTimelapse:  "Interval must be a positive duration and fps a number from 1 to 50",
+	ErrGridScale:         "The grid requires a scale of at least 2",
+	ErrInvalidImage:      "Image must be a PNG, GIF or JPEG file within the size limits",
+	ErrInvalidImport:     "Width and height must fit in the canvas, filter nearest or box and colors a number from 2 to 256",
 
 	// 401 Unauthorized
 	ErrInvalidCredentials:         "Invalid email or password",
@@ -59,13 +93,21 @@ var clientErrorCodes = map[ClientErrorCode]string{
 	ErrForbiddenCanvasAccess:      "You do not have permission to access this canvas",
 	ErrNotCanvasOwner:             "User is not the owner",
 	ErrAccessRulesUpdateForbidden: "User not allowd to update access rules",
+	ErrCanvasEditForbidden:        "User not allowed to edit this canvas",
+	ErrCommentEditForbidden:       "User not allowed to modify this comment",
 
 	// 404 Not Found
-	ErrUserNotFound:   "User not found",
-	ErrCanvasNotFound: "Canvas does not exist",
+	ErrUserNotFound:    "User not found",
+	ErrCanvasNotFound:  "Canvas does not exist",
+	ErrLayerNotFound:   "Layer does not exist",
+	ErrCommentNotFound: "Comment does not exist",
+	ErrVersionNotFound: "Version does not exist",
 
 	// 409 Conflict
 	ErrUserAlreadyRegistered: "User already registered",
+	ErrLastLayer:             "A canvas must have at least one layer",
+	ErrPaletteFull:           "Palette cannot hold more than 256 colors",
+	ErrLayerLocked:           "Layer is locked",
 }
 
 func ServerError(w http.ResponseWriter, r *http.Request, err error, msg string) {
@@ -0,0 +1,131 @@
+package websocket
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/websocket/msg"
+)
+
+// Reasons sent to clients when their access to a room changes
+const (
+	ReasonRoleChanged       = "ROLE_CHANGED"
+	ReasonAccessRemoved     = "ACCESS_REMOVED"
+	ReasonLinkAccessChanged = "LINK_ACCESS_CHANGED"
+	ReasonCanvasDeleted     = "CANVAS_DELETED"
+)
+
+// RefreshUserAccess reloads the user's access to the canvas and applies it to every connection the user has in the canvas room.
+// Connections are kicked from the room if the user no longer has access to the canvas.
+func (h *Hub) RefreshUserAccess(canvasID, userID, reason string) {
+	room := h.getRoom(canvasID)
+	if room == nil {
+		return
+	}
+
+	h.refreshAccess(room, userID, reason)
+}
+
+// RefreshRoomAccess reloads the access of every user in the canvas room, used when the canvas link access changes.
+func (h *Hub) RefreshRoomAccess(canvasID, reason string) {
+	room := h.getRoom(canvasID)
+	if room == nil {
+		return
+	}
+
+	for _, userID := range room.UserIDs() {
+		h.refreshAccess(room, userID, reason)
+	}
+}
+
+func (h *Hub) refreshAccess(room *Room, userID, reason string) {
+	clients := room.UserClients(userID)
+	if len(clients) == 0 {
+		return
+	}
+
+	userAccess, err := h.services.CanvasService.GetUserCanvasAccess(room.CanvasID, userID)
+	if err != nil {
+		if !errors.Is(err, types.ErrUserAccessDenied) && !errors.Is(err, types.ErrCanvasDoesNotExist) {
+			slog.Error("Failed to refresh user access", "canvasID", room.CanvasID, "userID", userID, "Error", err.Error())
+			return
+		}
+
+		for _, c := range clients {
+			sendMessage(c, msg.AccessRevokedMsg, &msg.AccessRevoked{
+				RoomId: room.CanvasID,
+				Reason: reason,
+			})
+			h.removeFromRoom(c, room)
+		}
+		return
+	}
+
+	if !room.SetUserPerms(userID, &userAccess) {
+		return
+	}
+
+	for _, c := range clients {
+		sendMessage(c, msg.AccessUpdatedMsg, &msg.AccessUpdated{
+			RoomId:     room.CanvasID,
+			AccessRole: uint32(userAccess.AccessRole),
+			Reason:     reason,
+		})
+	}
+}
+
+// SetUserPerms replaces the permissions of every connection of the user in the room.
+// Returns true if the user's access role changed.
+func (r *Room) SetUserPerms(userID string, accessRules *types.UserAccess) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := false
+	for _, c := range r.Clients {
+		if c.WSClient.ID != userID {
+			continue
+		}
+
+		if c.Perms == nil || c.Perms.AccessRole != accessRules.AccessRole {
+			changed = true
+		}
+		c.Perms = accessRules
+	}
+
+	return changed
+}
+
+// UserClients returns the connections of the user that are in the room.
+func (r *Room) UserClients(userID string) []*WSClient {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	clients := []*WSClient{}
+	for _, c := range r.Clients {
+		if c.WSClient.ID == userID {
+			clients = append(clients, c.WSClient)
+		}
+	}
+
+	return clients
+}
+
+// UserIDs returns the IDs of the users in the room.
+func (r *Room) UserIDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	userIDs := make([]string, 0, len(r.Clients))
+	seen := make(map[string]bool, len(r.Clients))
+	for _, c := range r.Clients {
+		if seen[c.WSClient.ID] {
+			continue
+		}
+
+		seen[c.WSClient.ID] = true
+		userIDs = append(userIDs, c.WSClient.ID)
+	}
+
+	return userIDs
+}
@@ -0,0 +1,140 @@
+package websocket
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/CDavidSV/Pixio/config"
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/websocket/msg"
+	"google.golang.org/protobuf/proto"
+)
+
+// chatLimit counts the chat messages a user sent in the current rate limit window.
+type chatLimit struct {
+	windowStart time.Time
+	count       int
+}
+
+// AllowChat returns the client's membership in the room if it is allowed to send a chat message right now.
+// Viewers can only chat while viewer chat is enabled, and every user is limited to config.ChatRateLimit messages per config.ChatRateInterval.
+func (r *Room) AllowChat(client *WSClient) (*ClientWithPerms, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member, ok := r.Clients[client.connID]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+
+	if !r.ViewerChat && !member.HasRole(types.Editor) {
+		return nil, ErrChatDisabled
+	}
+
+	// The limit is shared by every connection of the user
+	now := time.Now()
+	limit, ok := r.chatLimits[client.ID]
+	if !ok || now.Sub(limit.windowStart) >= config.ChatRateInterval {
+		limit = &chatLimit{windowStart: now}
+		r.chatLimits[client.ID] = limit
+	}
+
+	if limit.count >= config.ChatRateLimit {
+		return nil, ErrRateLimited
+	}
+
+	limit.count++
+	return member, nil
+}
+
+// pruneChatLimits removes the rate limit windows that have ended, so users that left the room don't keep an entry.
+func (r *Room) pruneChatLimits() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for userID, limit := range r.chatLimits {
+		if now.Sub(limit.windowStart) >= config.ChatRateInterval {
+			delete(r.chatLimits, userID)
+		}
+	}
+}
+
+// SetViewerChat applies the viewer chat setting to the canvas room and notifies every client in the room.
+func (h *Hub) SetViewerChat(canvasID string, enabled bool) {
+	room := h.getRoom(canvasID)
+	if room == nil {
+		return
+	}
+
+	room.mu.Lock()
+	changed := room.ViewerChat != enabled
+	room.ViewerChat = enabled
+	room.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	h.broadcastToCanvas(canvasID, msg.ChatSettingsMsg, &msg.ChatSettings{
+		RoomId:     canvasID,
+		ViewerChat: enabled,
+	})
+}
+
+func (h *Hub) sendChatMessage(client *WSClient, payload []byte) {
+	chatMessage := &msg.ChatMessage{}
+	if err := proto.Unmarshal(payload, chatMessage); err != nil {
+		sendError(client, msg.ChatMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	room := client.GetRoom(chatMessage.RoomId)
+	if room == nil {
+		sendError(client, msg.ChatMsg, ErrRoomNotFound.Error())
+		return
+	}
+
+	content := strings.TrimSpace(chatMessage.Content)
+	if content == "" || !utf8.ValidString(content) {
+		sendError(client, msg.ChatMsg, ErrInvalidChatMessage.Error())
+		return
+	}
+
+	if utf8.RuneCountInString(content) > config.ChatMaxLength {
+		sendError(client, msg.ChatMsg, ErrChatMessageTooLong.Error())
+		return
+	}
+
+	member, err := room.AllowChat(client)
+	if err != nil {
+		sendError(client, msg.ChatMsg, err.Error())
+		return
+	}
+
+	message, err := h.queries.CreateChatMessage(room.CanvasID, client.ID, content)
+	if err != nil {
+		slog.Error("Failed to save chat message", "canvasID", room.CanvasID, "userID", client.ID, "error", err)
+		sendError(client, msg.ChatMsg, ErrUnexpected.Error())
+		return
+	}
+
+	msgBytes, err := encodeMessage(msg.ChatMsg, &msg.ChatMessageReceived{
+		RoomId:    room.CanvasID,
+		MessageId: message.ID,
+		UserId:    message.UserID,
+		Username:  member.User.Username,
+		AvatarUrl: string(member.User.AvatarURL),
+		Content:   message.Content,
+		CreatedAt: message.CreatedAt.UnixMilli(),
+	})
+	if err != nil {
+		slog.Error("Failed to encode chat message", "canvasID", room.CanvasID, "error", err)
+		return
+	}
+
+	// The sender also receives the message so that it learns its ID and timestamp
+	broadcastMessage(nil, room, msgBytes)
+}
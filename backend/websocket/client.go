@@ -2,11 +2,21 @@ package websocket
 
 import (
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/CDavidSV/Pixio/config"
 	"github.com/CDavidSV/Pixio/utils"
 	"github.com/gorilla/websocket"
 )
 
+// SendStats counts the outbound messages that could not be delivered because a client was too slow.
+type SendStats struct {
+	DroppedMessages     atomic.Uint64 // Coalescable messages dropped because the client's queue was filling up
+	SlowClientsEvicted  atomic.Uint64 // Clients disconnected because their queue was full
+	DroppedOnDisconnect atomic.Uint64 // Messages that were not queued because the client had been disconnected
+}
+
 type WSClient struct {
 	ID          string
 	connID      string
@@ -14,17 +24,89 @@ type WSClient struct {
 	conn        *websocket.Conn
 	joinedRooms map[string]*Room
 	mu          sync.RWMutex
+	done        chan struct{} // Closed when the connection is closed
+	closeOnce   sync.Once
+	closeMsg    []byte // Close frame sent by the writer once done is closed
+	dropped     atomic.Uint64
+	stats       *SendStats
+	latency     atomic.Int64 // Last measured round-trip time in nanoseconds
 }
 
-func NewClient(userID string, conn *websocket.Conn) *WSClient {
+func NewClient(userID string, conn *websocket.Conn, stats *SendStats) *WSClient {
 	return &WSClient{
 		ID:          userID,
 		connID:      utils.GenerateID(),
 		conn:        conn,
-		send:        make(chan []byte),
+		send:        make(chan []byte, config.WSSendQueueSize),
 		joinedRooms: make(map[string]*Room),
 		mu:          sync.RWMutex{},
+		done:        make(chan struct{}),
+		stats:       stats,
+	}
+}
+
+// enqueue adds the message to the client's outbound queue without blocking.
+// Coalescable messages (e.g. cursor updates) are dropped once the queue is mostly full, leaving room for the rest.
+// If the queue is completely full the client is considered too slow and disconnected.
+func (c *WSClient) enqueue(message []byte, coalescable bool) bool {
+	select {
+	case <-c.done:
+		c.stats.DroppedOnDisconnect.Add(1)
+		return false
+	default:
 	}
+
+	if coalescable && len(c.send) >= cap(c.send)*3/4 {
+		c.dropped.Add(1)
+		c.stats.DroppedMessages.Add(1)
+		return false
+	}
+
+	select {
+	case c.send <- message:
+		return true
+	default:
+		c.dropped.Add(1)
+		if c.Close(websocket.ClosePolicyViolation, "send queue overflow") {
+			c.stats.SlowClientsEvicted.Add(1)
+		}
+		return false
+	}
+}
+
+// queueSpace returns the number of messages that can be queued before the client's send queue is full.
+func (c *WSClient) queueSpace() int {
+	return cap(c.send) - len(c.send)
+}
+
+// Close marks the connection as closed with the given code and reason without blocking, so it can be called while holding a room lock.
+// The writer sends the close frame and closes the connection. It is safe to call multiple times and from multiple goroutines,
+// only the first call closes the connection and returns true.
+func (c *WSClient) Close(code int, reason string) bool {
+	closed := false
+	c.closeOnce.Do(func() {
+		c.closeMsg = websocket.FormatCloseMessage(code, reason)
+		close(c.done)
+		closed = true
+	})
+
+	return closed
+}
+
+// closeConn sends the close frame and closes the connection. Must only be called by the writer, or before the writer starts, after Close.
+func (c *WSClient) closeConn() {
+	c.conn.WriteControl(websocket.CloseMessage, c.closeMsg, time.Now().Add(time.Second))
+	c.conn.Close()
+}
+
+// Latency returns the last round-trip time measured with a ping, or zero if no pong has been received yet.
+func (c *WSClient) Latency() time.Duration {
+	return time.Duration(c.latency.Load())
+}
+
+// Dropped returns the number of messages that were not delivered to this client.
+func (c *WSClient) Dropped() uint64 {
+	return c.dropped.Load()
 }
 
 func (c *WSClient) AddRoom(room *Room) {
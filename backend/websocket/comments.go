@@ -0,0 +1,43 @@
+package websocket
+
+import (
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/websocket/msg"
+)
+
+// commentMessage converts a comment into its message representation without its replies.
+func commentMessage(comment types.Comment) *msg.Comment {
+	return &msg.Comment{
+		RoomId:     comment.CanvasID,
+		Id:         comment.ID,
+		ParentId:   string(comment.ParentID),
+		UserId:     comment.UserID,
+		Username:   comment.Username,
+		AvatarUrl:  string(comment.AvatarURL),
+		Content:    comment.Content,
+		Anchor:     &msg.Rect{X: int32(comment.X), Y: int32(comment.Y), Width: uint32(comment.Width), Height: uint32(comment.Height)},
+		Resolved:   comment.Resolved,
+		ResolvedBy: string(comment.ResolvedBy),
+		CreatedAt:  comment.CreatedAt.UnixMilli(),
+		UpdatedAt:  comment.UpdatedAt.UnixMilli(),
+	}
+}
+
+// CommentCreated notifies the canvas room of a new comment thread or reply.
+func (h *Hub) CommentCreated(comment types.Comment) {
+	h.broadcastToCanvas(comment.CanvasID, msg.CommentCreateMsg, commentMessage(comment))
+}
+
+// CommentUpdated notifies the canvas room of an edited, resolved or reopened comment.
+func (h *Hub) CommentUpdated(comment types.Comment) {
+	h.broadcastToCanvas(comment.CanvasID, msg.CommentUpdateMsg, commentMessage(comment))
+}
+
+// CommentDeleted notifies the canvas room of a deleted comment, clients drop the replies of deleted threads.
+func (h *Hub) CommentDeleted(comment types.Comment) {
+	h.broadcastToCanvas(comment.CanvasID, msg.CommentDeleteMsg, &msg.CommentDeleted{
+		RoomId:    comment.CanvasID,
+		CommentId: comment.ID,
+		ParentId:  string(comment.ParentID),
+	})
+}
@@ -0,0 +1,121 @@
+package websocket
+
+import (
+	"log/slog"
+
+	"github.com/CDavidSV/Pixio/websocket/msg"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxToolNameLength is the maximum length of the tool name attached to a cursor.
+const maxToolNameLength = 32
+
+// setCursor records the latest cursor position of the connection, replacing any position that was not broadcast yet.
+// Positions are kept per connection so that a user with several tabs open has a separate cursor in each one.
+func (r *Room) setCursor(cursor *msg.MousePositionUpdate) {
+	r.cursorMu.Lock()
+	defer r.cursorMu.Unlock()
+
+	if r.cursors == nil {
+		r.cursors = make(map[string]*msg.MousePositionUpdate)
+	}
+	r.cursors[cursor.ConnId] = cursor
+}
+
+// dropCursor discards the pending cursor position of the connection when it leaves the room.
+func (r *Room) dropCursor(client *WSClient) {
+	r.cursorMu.Lock()
+	defer r.cursorMu.Unlock()
+
+	delete(r.cursors, client.connID)
+}
+
+// flushCursors broadcasts the cursor positions received since the last flush as a single batch.
+// Each client gets the latest position of every connection except its own.
+func (r *Room) flushCursors() {
+	r.cursorMu.Lock()
+	cursors := r.cursors
+	r.cursors = nil
+	r.cursorMu.Unlock()
+
+	if len(cursors) == 0 {
+		return
+	}
+
+	batch := make([]*msg.MousePositionUpdate, 0, len(cursors))
+	for _, cursor := range cursors {
+		batch = append(batch, cursor)
+	}
+
+	encode := func(cursors []*msg.MousePositionUpdate) []byte {
+		message, err := encodeMessage(msg.CursorBatchMsg, &msg.CursorBatch{
+			RoomId:  r.CanvasID,
+			Cursors: cursors,
+		})
+		if err != nil {
+			slog.Error("Failed to encode cursor batch", "canvasID", r.CanvasID, "error", err)
+			return nil
+		}
+
+		return message
+	}
+
+	message := encode(batch)
+	if message == nil {
+		return
+	}
+
+	// Cursor batches can be dropped for slow clients since the next batch carries newer positions
+	for _, c := range r.recipients(nil) {
+		own, ok := cursors[c.connID]
+		if !ok {
+			c.enqueue(message, true)
+			continue
+		}
+
+		if len(batch) == 1 {
+			continue
+		}
+
+		others := make([]*msg.MousePositionUpdate, 0, len(batch)-1)
+		for _, cursor := range batch {
+			if cursor != own {
+				others = append(others, cursor)
+			}
+		}
+
+		if filtered := encode(others); filtered != nil {
+			c.enqueue(filtered, true)
+		}
+	}
+}
+
+func (h *Hub) updateCursorPosition(client *WSClient, payload []byte) {
+	mousePos := &msg.MousePosition{}
+	err := proto.Unmarshal(payload, mousePos)
+	if err != nil {
+		sendError(client, msg.MousePosUpdateMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	room := client.GetRoom(mousePos.RoomId)
+	if room == nil {
+		sendError(client, msg.MousePosUpdateMsg, ErrRoomNotFound.Error())
+		return
+	}
+
+	if len(mousePos.Tool) > maxToolNameLength {
+		sendError(client, msg.MousePosUpdateMsg, ErrInvalidToolParams.Error())
+		return
+	}
+
+	// The position is broadcast with the next cursor batch, a newer position replaces it in the meantime
+	room.setCursor(&msg.MousePositionUpdate{
+		UserId: client.ID,
+		ConnId: client.connID,
+		X:      mousePos.X,
+		Y:      mousePos.Y,
+		Tool:   mousePos.Tool,
+		Color:  mousePos.Color,
+	})
+}
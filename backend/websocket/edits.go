@@ -0,0 +1,69 @@
+package websocket
+
+import (
+	"maps"
+	"time"
+
+	"github.com/CDavidSV/Pixio/services"
+)
+
+// attribute records the user as the last one to change the pixels. Must be called while holding r.mu.
+func (r *Room) attribute(userID string, changes []pixelChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	if r.pendingEdits == nil {
+		r.pendingEdits = make(map[string]services.PixelEdits)
+	}
+
+	edits, ok := r.pendingEdits[userID]
+	if !ok {
+		edits = make(services.PixelEdits)
+		r.pendingEdits[userID] = edits
+	}
+
+	now := time.Now()
+	for _, c := range changes {
+		edits[c.index] = now
+	}
+}
+
+// restorePendingEdits adds back edits that failed to be saved, keeping the latest change of each pixel. Must be called while holding r.mu.
+func (r *Room) restorePendingEdits(edits map[string]services.PixelEdits) {
+	if r.pendingEdits == nil {
+		r.pendingEdits = make(map[string]services.PixelEdits)
+	}
+
+	for userID, pixels := range edits {
+		pending, ok := r.pendingEdits[userID]
+		if !ok {
+			r.pendingEdits[userID] = pixels
+			continue
+		}
+
+		for index, t := range pixels {
+			if t.After(pending[index]) {
+				pending[index] = t
+			}
+		}
+	}
+}
+
+// PendingEdits returns a copy of the edits of the live canvas room that are not saved yet.
+func (h *Hub) PendingEdits(canvasID string) map[string]services.PixelEdits {
+	room := h.getRoom(canvasID)
+	if room == nil {
+		return nil
+	}
+
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	edits := make(map[string]services.PixelEdits, len(room.pendingEdits))
+	for userID, pixels := range room.pendingEdits {
+		edits[userID] = maps.Clone(pixels)
+	}
+
+	return edits
+}
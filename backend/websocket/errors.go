@@ -19,4 +19,22 @@ var (
 	ErrFetchingUserAccess = errors.New("CANNOT_FETCH_USER_ACCESS")
 	ErrUnexpected         = errors.New("UNEXPECTED_SERVER_ERROR")
 	ErrDecodingMsg        = errors.New("ERROR_DECODING_MESSAGE")
+	ErrCanvasNotLoaded    = errors.New("CANVAS_NOT_LOADED")
+	ErrInvalidPixelData   = errors.New("INVALID_PIXEL_DATA")
+	ErrFetchingUser       = errors.New("CANNOT_FETCH_USER")
+	ErrNothingToUndo      = errors.New("NOTHING_TO_UNDO")
+	ErrNothingToRedo      = errors.New("NOTHING_TO_REDO")
+	ErrLayerNotFound      = errors.New("LAYER_NOT_FOUND")
+	ErrLayerLocked        = errors.New("LAYER_LOCKED")
+	ErrInvalidToolParams  = errors.New("INVALID_TOOL_PARAMS")
+	ErrInvalidSelection   = errors.New("INVALID_SELECTION")
+	ErrClipboardEmpty     = errors.New("CLIPBOARD_EMPTY")
+	ErrColorNotInPalette  = errors.New("COLOR_NOT_IN_PALETTE")
+	ErrPaletteFull        = errors.New("PALETTE_FULL")
+	ErrInvalidColorIndex  = errors.New("INVALID_COLOR_INDEX")
+	ErrInvalidColor       = errors.New("INVALID_COLOR")
+	ErrChatDisabled       = errors.New("CHAT_DISABLED")
+	ErrInvalidChatMessage = errors.New("INVALID_CHAT_MESSAGE")
+	ErrChatMessageTooLong = errors.New("CHAT_MESSAGE_TOO_LONG")
+	ErrRateLimited        = errors.New("RATE_LIMITED")
 )
@@ -3,6 +3,7 @@ package websocket
 import (
 	"log/slog"
 
+	"github.com/CDavidSV/Pixio/types"
 	"github.com/CDavidSV/Pixio/websocket/msg"
 	"google.golang.org/protobuf/proto"
 )
@@ -46,7 +47,7 @@ func sendError(client *WSClient, msgType msg.WSMessageType, errMsg string) {
 		return
 	}
 
-	client.send <- msgBytes
+	client.enqueue(msgBytes, false)
 }
 
 func sendMessage(client *WSClient, msgType msg.WSMessageType, msg proto.Message) {
@@ -56,18 +57,57 @@ func sendMessage(client *WSClient, msgType msg.WSMessageType, msg proto.Message)
 		return
 	}
 
-	client.send <- msgBytes
+	client.enqueue(msgBytes, false)
 }
 
 func broadcastMessage(sender *WSClient, room *Room, msg []byte) {
-	room.mu.RLock()
-	defer room.mu.RUnlock()
-	for _, c := range room.Clients {
-		// Don't send the message to the sender
-		if c.WSClient.ID == sender.ID {
+	for _, c := range room.recipients(sender) {
+		c.enqueue(msg, false)
+	}
+}
+
+// broadcastToCanvas sends the message to every client in the canvas room, if the canvas has one.
+// Used for changes made through the REST API that are not part of the room's operation log.
+func (h *Hub) broadcastToCanvas(canvasID string, msgType msg.WSMessageType, m proto.Message) {
+	room := h.getRoom(canvasID)
+	if room == nil {
+		return
+	}
+
+	msgBytes, err := encodeMessage(msgType, m)
+	if err != nil {
+		slog.Error("Failed to encode message", "canvasID", canvasID, "type", msgType, "error", err)
+		return
+	}
+
+	broadcastMessage(nil, room, msgBytes)
+}
+
+// recipients copies the connections in the room so that the room is not locked while queueing messages to them.
+func (r *Room) recipients(sender *WSClient) []*WSClient {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	recipients := make([]*WSClient, 0, len(r.Clients))
+	for _, c := range r.Clients {
+		// Don't send the message to the connection that sent it, other connections of the same user still need it
+		if c.WSClient == sender {
 			continue
 		}
 
-		c.WSClient.send <- msg
+		recipients = append(recipients, c.WSClient)
 	}
+
+	return recipients
+}
+
+// authorize checks that the client is in the room with at least the given role, sending an error to the client otherwise.
+func authorize(client *WSClient, room *Room, msgType msg.WSMessageType, role types.AccessRole) (*ClientWithPerms, bool) {
+	member, err := room.CheckRole(client, role)
+	if err != nil {
+		sendError(client, msgType, err.Error())
+		return nil, false
+	}
+
+	return member, true
 }
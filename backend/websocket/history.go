@@ -0,0 +1,216 @@
+package websocket
+
+import (
+	"errors"
+
+	"github.com/CDavidSV/Pixio/config"
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/websocket/msg"
+	"google.golang.org/protobuf/proto"
+)
+
+// pixelChange is the value of a pixel before and after a change, used to undo it.
+type pixelChange struct {
+	index  int
+	before types.Pixel
+	after  types.Pixel
+}
+
+// stroke groups the pixel changes a user made on a layer with the same stroke ID so that they are undone as a whole.
+type stroke struct {
+	id      string
+	layerID string
+	changes []pixelChange
+	indexes map[int]int // pixel index -> position in changes
+}
+
+type userHistory struct {
+	undo []*stroke
+	redo []*stroke
+}
+
+// recordStroke adds the changes to the user's undo history, merging them into the last stroke if it has the same ID.
+// Must be called while holding r.mu.
+func (r *Room) recordStroke(userID, layerID, strokeID string, changes []pixelChange) {
+	history, ok := r.history[userID]
+	if !ok {
+		history = &userHistory{}
+		r.history[userID] = history
+	}
+
+	// Any new change makes the undone strokes impossible to redo
+	history.redo = nil
+
+	var s *stroke
+	if n := len(history.undo); n > 0 && strokeID != "" && history.undo[n-1].id == strokeID && history.undo[n-1].layerID == layerID {
+		s = history.undo[n-1]
+	} else {
+		s = &stroke{
+			id:      strokeID,
+			layerID: layerID,
+			indexes: make(map[int]int),
+		}
+		history.undo = append(history.undo, s)
+
+		if overflow := len(history.undo) - config.RoomUndoHistorySize; overflow > 0 {
+			history.undo = append(history.undo[:0], history.undo[overflow:]...)
+		}
+	}
+
+	for _, c := range changes {
+		// Keep the value the pixel had before the stroke started
+		if i, exists := s.indexes[c.index]; exists {
+			s.changes[i].after = c.after
+			continue
+		}
+
+		s.indexes[c.index] = len(s.changes)
+		s.changes = append(s.changes, c)
+	}
+}
+
+// Undo reverts the user's last stroke and broadcasts the resulting pixels to everyone in the room.
+// Pixels that were overwritten by someone else since the stroke are left untouched.
+func (r *Room) Undo(client *WSClient) ([]*msg.PixelUpdate, error) {
+	return r.moveStroke(client, true)
+}
+
+// Redo reapplies the user's last undone stroke, skipping pixels that were changed since it was undone.
+func (r *Room) Redo(client *WSClient) ([]*msg.PixelUpdate, error) {
+	return r.moveStroke(client, false)
+}
+
+func (r *Room) moveStroke(client *WSClient, undo bool) ([]*msg.PixelUpdate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.loadStatus != Loaded {
+		return nil, ErrCanvasNotLoaded
+	}
+
+	history, ok := r.history[client.ID]
+	if !ok {
+		history = &userHistory{}
+	}
+
+	from, to := &history.undo, &history.redo
+	if !undo {
+		from, to = to, from
+	}
+
+	if len(*from) == 0 {
+		if undo {
+			return nil, ErrNothingToUndo
+		}
+		return nil, ErrNothingToRedo
+	}
+
+	s := (*from)[len(*from)-1]
+
+	// Strokes on deleted layers can't be applied anymore, they are consumed without changes
+	layer := r.getLayer(s.layerID)
+	if layer != nil && layer.Locked {
+		return nil, ErrLayerLocked
+	}
+
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, s)
+
+	if layer == nil {
+		return []*msg.PixelUpdate{}, nil
+	}
+
+	updates := make([]*msg.PixelUpdate, 0, len(s.changes))
+	applied := make([]pixelChange, 0, len(s.changes))
+	for _, c := range s.changes {
+		expected, target := c.after, c.before
+		if !undo {
+			expected, target = c.before, c.after
+		}
+
+		if c.index >= len(layer.PixelData) || layer.PixelData[c.index] != expected {
+			continue
+		}
+
+		layer.PixelData[c.index] = target
+		r.markDirty(layer.ID, c.index)
+		updates = append(updates, r.pixelUpdate(c.index, target))
+		applied = append(applied, c)
+	}
+	r.recordChanges(client.ID, applied)
+
+	if len(updates) == 0 {
+		return updates, nil
+	}
+
+	// The user's own connections need the result too, so it is sent to everyone
+	_, err := r.commit(nil, msg.SetPixelsMsg, func(seq uint64) proto.Message {
+		return &msg.PixelsUpdate{
+			UserId:   client.ID,
+			Pixels:   updates,
+			Revision: seq,
+			LayerId:  layer.ID,
+		}
+	})
+
+	return updates, err
+}
+
+// pixelUpdate returns the message representation of the pixel at the given index.
+func (r *Room) pixelUpdate(index int, pixel types.Pixel) *msg.PixelUpdate {
+	return &msg.PixelUpdate{
+		X: uint32(index % int(r.Width)),
+		Y: uint32(index / int(r.Width)),
+		R: uint32(pixel.R),
+		G: uint32(pixel.G),
+		B: uint32(pixel.B),
+		A: uint32(pixel.A),
+	}
+}
+
+func (h *Hub) undo(client *WSClient, payload []byte) {
+	undo := &msg.Undo{}
+	if err := proto.Unmarshal(payload, undo); err != nil {
+		sendError(client, msg.UndoMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	h.applyHistory(client, msg.UndoMsg, undo.RoomId, true)
+}
+
+func (h *Hub) redo(client *WSClient, payload []byte) {
+	redo := &msg.Redo{}
+	if err := proto.Unmarshal(payload, redo); err != nil {
+		sendError(client, msg.RedoMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	h.applyHistory(client, msg.RedoMsg, redo.RoomId, false)
+}
+
+func (h *Hub) applyHistory(client *WSClient, msgType msg.WSMessageType, roomID string, undo bool) {
+	room := client.GetRoom(roomID)
+	if room == nil {
+		sendError(client, msgType, ErrRoomNotFound.Error())
+		return
+	}
+
+	if _, ok := authorize(client, room, msgType, types.Editor); !ok {
+		return
+	}
+
+	var err error
+	if undo {
+		_, err = room.Undo(client)
+	} else {
+		_, err = room.Redo(client)
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrCanvasNotLoaded) || errors.Is(err, ErrNothingToUndo) || errors.Is(err, ErrNothingToRedo) || errors.Is(err, ErrLayerLocked) {
+			sendError(client, msgType, err.Error())
+		} else {
+			sendError(client, msgType, ErrMarshallingMsg.Error())
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package websocket
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// historyStep is an action of a user in a history test.
+type historyStep struct {
+	user    string
+	action  string // paint, undo, redo, lock or delete
+	stroke  string
+	index   int
+	pixel   types.Pixel
+	wantErr error
+}
+
+func paintStep(user, stroke string, index int, pixel types.Pixel) historyStep {
+	return historyStep{user: user, action: "paint", stroke: stroke, index: index, pixel: pixel}
+}
+
+func undoStep(user string, wantErr error) historyStep {
+	return historyStep{user: user, action: "undo", wantErr: wantErr}
+}
+
+func redoStep(user string, wantErr error) historyStep {
+	return historyStep{user: user, action: "redo", wantErr: wantErr}
+}
+
+// layerStep locks or deletes the layer the strokes are painted on.
+func layerStep(action string) historyStep {
+	return historyStep{action: action}
+}
+
+func TestUndoRedo(t *testing.T) {
+	red := types.Pixel{R: 255, A: 255}
+	blue := types.Pixel{B: 255, A: 255}
+	transparent := types.Pixel{}
+
+	tests := []struct {
+		name  string
+		steps []historyStep
+		want  []types.Pixel
+	}{
+		{
+			name:  "nothing to undo",
+			steps: []historyStep{undoStep("a", ErrNothingToUndo)},
+			want:  []types.Pixel{transparent, transparent},
+		},
+		{
+			name:  "nothing to redo",
+			steps: []historyStep{paintStep("a", "s1", 0, red), redoStep("a", ErrNothingToRedo)},
+			want:  []types.Pixel{red, transparent},
+		},
+		{
+			name:  "undo reverts the stroke",
+			steps: []historyStep{paintStep("a", "s1", 0, red), paintStep("a", "s1", 1, red), undoStep("a", nil)},
+			want:  []types.Pixel{transparent, transparent},
+		},
+		{
+			name:  "undo reverts one stroke at a time",
+			steps: []historyStep{paintStep("a", "s1", 0, red), paintStep("a", "s2", 1, red), undoStep("a", nil)},
+			want:  []types.Pixel{red, transparent},
+		},
+		{
+			name:  "strokes keep the value from before they started",
+			steps: []historyStep{paintStep("a", "s1", 0, red), paintStep("a", "s1", 0, blue), undoStep("a", nil)},
+			want:  []types.Pixel{transparent, transparent},
+		},
+		{
+			name:  "undo skips pixels changed by someone else",
+			steps: []historyStep{paintStep("a", "s1", 0, red), paintStep("a", "s1", 1, red), paintStep("b", "s2", 1, blue), undoStep("a", nil)},
+			want:  []types.Pixel{transparent, blue},
+		},
+		{
+			name:  "undo only reverts the user's strokes",
+			steps: []historyStep{paintStep("a", "s1", 0, red), paintStep("b", "s2", 1, blue), undoStep("a", nil)},
+			want:  []types.Pixel{transparent, blue},
+		},
+		{
+			name:  "redo reapplies the stroke",
+			steps: []historyStep{paintStep("a", "s1", 0, red), undoStep("a", nil), redoStep("a", nil)},
+			want:  []types.Pixel{red, transparent},
+		},
+		{
+			name:  "redo skips pixels changed since the undo",
+			steps: []historyStep{paintStep("a", "s1", 0, red), paintStep("a", "s1", 1, red), undoStep("a", nil), paintStep("b", "s2", 0, blue), redoStep("a", nil)},
+			want:  []types.Pixel{blue, red},
+		},
+		{
+			name:  "a new stroke clears the redo history",
+			steps: []historyStep{paintStep("a", "s1", 0, red), undoStep("a", nil), paintStep("a", "s2", 1, blue), redoStep("a", ErrNothingToRedo)},
+			want:  []types.Pixel{transparent, blue},
+		},
+		{
+			name:  "strokes on locked layers can't be undone",
+			steps: []historyStep{paintStep("a", "s1", 0, red), layerStep("lock"), undoStep("a", ErrLayerLocked), undoStep("a", ErrLayerLocked)},
+			want:  []types.Pixel{red, transparent},
+		},
+		{
+			name:  "strokes on deleted layers are consumed",
+			steps: []historyStep{paintStep("a", "s1", 0, red), paintStep("a", "s2", 1, red), layerStep("delete"), undoStep("a", nil), undoStep("a", nil), undoStep("a", ErrNothingToUndo)},
+			want:  []types.Pixel{red, red},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layer := &types.LoadedLayer{ID: "layer", Visible: true, Opacity: 100, PixelData: make([]types.Pixel, 2)}
+			r := &Room{
+				Width:      2,
+				Height:     1,
+				Layers:     []*types.LoadedLayer{layer},
+				loadStatus: Loaded,
+				history:    make(map[string]*userHistory),
+			}
+
+			for i, step := range tt.steps {
+				client := &WSClient{ID: step.user}
+
+				var err error
+				switch step.action {
+				case "paint":
+					r.recordStroke(step.user, layer.ID, step.stroke, []pixelChange{{index: step.index, before: layer.PixelData[step.index], after: step.pixel}})
+					layer.PixelData[step.index] = step.pixel
+				case "lock":
+					layer.Locked = true
+				case "delete":
+					r.Layers = nil
+				case "undo":
+					_, err = r.Undo(client)
+				case "redo":
+					_, err = r.Redo(client)
+				}
+
+				if !errors.Is(err, step.wantErr) {
+					t.Fatalf("step %d (%s) error = %v, want %v", i, step.action, err, step.wantErr)
+				}
+			}
+
+			for i, want := range tt.want {
+				if layer.PixelData[i] != want {
+					t.Errorf("pixel %d = %v, want %v", i, layer.PixelData[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package websocket
+
+import (
+	"errors"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
+)
+
+// ImportImage writes the image into the layer of the canvas with its top left corner at (x, y), as a single stroke of the user.
+// Pixels outside of the canvas are dropped and canvases with a locked palette get the closest palette colors.
+// Canvases that are not loaded in a room get the image written to their stored layer instead.
+func (h *Hub) ImportImage(canvasID, layerID, userID string, x, y, width, height int, pixels []types.Pixel) error {
+	// A room that is created or loading while the stored layer is changed waits for the import before loading it
+	unlock := h.lockCanvas(canvasID)
+	defer unlock()
+
+	room := h.getRoom(canvasID)
+	if room == nil {
+		return h.services.CanvasService.ImportImage(canvasID, layerID, userID, x, y, width, height, pixels)
+	}
+
+	err := room.applyEdit(userID, layerID, utils.GenerateID(), func(_ *types.LoadedLayer, set func(x, y int, pixel types.Pixel)) error {
+		for py := range height {
+			for px := range width {
+				set(x+px, y+py, room.paletteColor(pixels[py*width+px]))
+			}
+		}
+
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, ErrCanvasNotLoaded):
+		return h.services.CanvasService.ImportImage(canvasID, layerID, userID, x, y, width, height, pixels)
+	case errors.Is(err, ErrLayerNotFound):
+		return types.ErrLayerNotFound
+	case errors.Is(err, ErrLayerLocked):
+		return types.ErrLayerLocked
+	}
+
+	return err
+}
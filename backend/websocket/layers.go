@@ -0,0 +1,144 @@
+package websocket
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/websocket/msg"
+	"google.golang.org/protobuf/proto"
+)
+
+// layerMessage converts a layer into its message representation without pixel data.
+func layerMessage(layer *types.LoadedLayer, position int) *msg.Layer {
+	return &msg.Layer{
+		Id:       layer.ID,
+		Name:     layer.Name,
+		Position: uint32(position),
+		Visible:  layer.Visible,
+		Opacity:  uint32(layer.Opacity),
+		Locked:   layer.Locked,
+	}
+}
+
+// AddLayer adds a blank layer on top of the canvas room's layers.
+func (h *Hub) AddLayer(canvasID string, layer types.Layer) {
+	h.updateLayers(canvasID, func(r *Room) bool {
+		if r.getLayer(layer.ID) != nil {
+			return false
+		}
+
+		r.Layers = append(r.Layers, &types.LoadedLayer{
+			ID:        layer.ID,
+			CanvasID:  layer.CanvasID,
+			Name:      layer.Name,
+			Visible:   layer.Visible,
+			Opacity:   layer.Opacity,
+			Locked:    layer.Locked,
+			PixelData: make([]types.Pixel, int(r.Width)*int(r.Height)),
+			CreatedAt: layer.CreatedAt,
+		})
+		return true
+	})
+}
+
+// UpdateLayer applies the layer's name, visibility, opacity and lock state to the canvas room.
+// Returns false if the canvas is not loaded in a room.
+func (h *Hub) UpdateLayer(canvasID string, layer types.Layer) bool {
+	return h.updateLayers(canvasID, func(r *Room) bool {
+		loaded := r.getLayer(layer.ID)
+		if loaded == nil {
+			return false
+		}
+
+		loaded.Name = layer.Name
+		loaded.Visible = layer.Visible
+		loaded.Opacity = layer.Opacity
+		loaded.Locked = layer.Locked
+		r.markCanvasDirty()
+		return true
+	})
+}
+
+// RemoveLayer removes the layer from the canvas room.
+// Returns false if the canvas is not loaded in a room.
+func (h *Hub) RemoveLayer(canvasID, layerID string) bool {
+	return h.updateLayers(canvasID, func(r *Room) bool {
+		index := slices.IndexFunc(r.Layers, func(l *types.LoadedLayer) bool { return l.ID == layerID })
+		if index == -1 {
+			return false
+		}
+
+		r.Layers = slices.Delete(r.Layers, index, index+1)
+		delete(r.dirtyTiles, layerID)
+		r.markCanvasDirty()
+		return true
+	})
+}
+
+// ReorderLayers sorts the canvas room's layers in the given order, from bottom to top.
+// Returns false if the canvas is not loaded in a room.
+func (h *Hub) ReorderLayers(canvasID string, layerIDs []string) bool {
+	return h.updateLayers(canvasID, func(r *Room) bool {
+		if len(layerIDs) != len(r.Layers) {
+			return false
+		}
+
+		ordered := make([]*types.LoadedLayer, 0, len(layerIDs))
+		for _, id := range layerIDs {
+			layer := r.getLayer(id)
+			if layer == nil {
+				return false
+			}
+
+			ordered = append(ordered, layer)
+		}
+
+		r.Layers = ordered
+		r.markCanvasDirty()
+		return true
+	})
+}
+
+// updateLayers applies the change to a loaded canvas room and broadcasts the resulting layer list to every client in the room.
+// Rooms that are not loaded are skipped since they read the layers from the database when they load.
+// Returns false if the canvas is not loaded in a room.
+func (h *Hub) updateLayers(canvasID string, apply func(r *Room) bool) bool {
+	room := h.getRoom(canvasID)
+	if room == nil {
+		return false
+	}
+
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	if room.loadStatus != Loaded {
+		return false
+	}
+
+	if !apply(room) {
+		return true
+	}
+
+	for i, layer := range room.Layers {
+		layer.Position = i
+	}
+
+	_, err := room.commit(nil, msg.LayersUpdateMsg, func(seq uint64) proto.Message {
+		layers := make([]*msg.Layer, len(room.Layers))
+		for i, layer := range room.Layers {
+			layers[i] = layerMessage(layer, i)
+		}
+
+		return &msg.LayersUpdate{
+			RoomId:   room.CanvasID,
+			Layers:   layers,
+			Revision: seq,
+		}
+	})
+	if err != nil {
+		slog.Error("Failed to broadcast layers update", "canvasID", canvasID, "error", err)
+	}
+
+	return true
+}
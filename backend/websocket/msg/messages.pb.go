@@ -21,6 +21,150 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ShapeType int32
+
+const (
+	ShapeType_RECTANGLE ShapeType = 0
+	ShapeType_ELLIPSE   ShapeType = 1
+)
+
+// Enum value maps for ShapeType.
+var (
+	ShapeType_name = map[int32]string{
+		0: "RECTANGLE",
+		1: "ELLIPSE",
+	}
+	ShapeType_value = map[string]int32{
+		"RECTANGLE": 0,
+		"ELLIPSE":   1,
+	}
+)
+
+func (x ShapeType) Enum() *ShapeType {
+	p := new(ShapeType)
+	*p = x
+	return p
+}
+
+func (x ShapeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ShapeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_websocket_msg_messages_proto_enumTypes[0].Descriptor()
+}
+
+func (ShapeType) Type() protoreflect.EnumType {
+	return &file_websocket_msg_messages_proto_enumTypes[0]
+}
+
+func (x ShapeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ShapeType.Descriptor instead.
+func (ShapeType) EnumDescriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{0}
+}
+
+type BrushShape int32
+
+const (
+	BrushShape_SQUARE BrushShape = 0
+	BrushShape_CIRCLE BrushShape = 1
+)
+
+// Enum value maps for BrushShape.
+var (
+	BrushShape_name = map[int32]string{
+		0: "SQUARE",
+		1: "CIRCLE",
+	}
+	BrushShape_value = map[string]int32{
+		"SQUARE": 0,
+		"CIRCLE": 1,
+	}
+)
+
+func (x BrushShape) Enum() *BrushShape {
+	p := new(BrushShape)
+	*p = x
+	return p
+}
+
+func (x BrushShape) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BrushShape) Descriptor() protoreflect.EnumDescriptor {
+	return file_websocket_msg_messages_proto_enumTypes[1].Descriptor()
+}
+
+func (BrushShape) Type() protoreflect.EnumType {
+	return &file_websocket_msg_messages_proto_enumTypes[1]
+}
+
+func (x BrushShape) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BrushShape.Descriptor instead.
+func (BrushShape) EnumDescriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{1}
+}
+
+type PaletteAction int32
+
+const (
+	PaletteAction_ADD_COLOR      PaletteAction = 0
+	PaletteAction_UPDATE_COLOR   PaletteAction = 1
+	PaletteAction_REMOVE_COLOR   PaletteAction = 2
+	PaletteAction_REPLACE_COLORS PaletteAction = 3
+)
+
+// Enum value maps for PaletteAction.
+var (
+	PaletteAction_name = map[int32]string{
+		0: "ADD_COLOR",
+		1: "UPDATE_COLOR",
+		2: "REMOVE_COLOR",
+		3: "REPLACE_COLORS",
+	}
+	PaletteAction_value = map[string]int32{
+		"ADD_COLOR":      0,
+		"UPDATE_COLOR":   1,
+		"REMOVE_COLOR":   2,
+		"REPLACE_COLORS": 3,
+	}
+)
+
+func (x PaletteAction) Enum() *PaletteAction {
+	p := new(PaletteAction)
+	*p = x
+	return p
+}
+
+func (x PaletteAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PaletteAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_websocket_msg_messages_proto_enumTypes[2].Descriptor()
+}
+
+func (PaletteAction) Type() protoreflect.EnumType {
+	return &file_websocket_msg_messages_proto_enumTypes[2]
+}
+
+func (x PaletteAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PaletteAction.Descriptor instead.
+func (PaletteAction) EnumDescriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{2}
+}
+
 type WSMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
@@ -166,6 +310,8 @@ type MousePosition struct {
 	X             uint32                 `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
 	Y             uint32                 `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
 	RoomId        string                 `protobuf:"bytes,3,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Tool          string                 `protobuf:"bytes,4,opt,name=tool,proto3" json:"tool,omitempty"`
+	Color         *Color                 `protobuf:"bytes,5,opt,name=color,proto3" json:"color,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -221,11 +367,28 @@ func (x *MousePosition) GetRoomId() string {
 	return ""
 }
 
+func (x *MousePosition) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *MousePosition) GetColor() *Color {
+	if x != nil {
+		return x.Color
+	}
+	return nil
+}
+
 type MousePositionUpdate struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	X             uint32                 `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
 	Y             uint32                 `protobuf:"varint,3,opt,name=y,proto3" json:"y,omitempty"`
+	ConnId        string                 `protobuf:"bytes,4,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	Tool          string                 `protobuf:"bytes,5,opt,name=tool,proto3" json:"tool,omitempty"`
+	Color         *Color                 `protobuf:"bytes,6,opt,name=color,proto3" json:"color,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -281,16 +444,91 @@ func (x *MousePositionUpdate) GetY() uint32 {
 	return 0
 }
 
+func (x *MousePositionUpdate) GetConnId() string {
+	if x != nil {
+		return x.ConnId
+	}
+	return ""
+}
+
+func (x *MousePositionUpdate) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *MousePositionUpdate) GetColor() *Color {
+	if x != nil {
+		return x.Color
+	}
+	return nil
+}
+
+type CursorBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Cursors       []*MousePositionUpdate `protobuf:"bytes,2,rep,name=cursors,proto3" json:"cursors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CursorBatch) Reset() {
+	*x = CursorBatch{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CursorBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CursorBatch) ProtoMessage() {}
+
+func (x *CursorBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CursorBatch.ProtoReflect.Descriptor instead.
+func (*CursorBatch) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CursorBatch) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *CursorBatch) GetCursors() []*MousePositionUpdate {
+	if x != nil {
+		return x.Cursors
+	}
+	return nil
+}
+
 type JoinRoom struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	CanvasId      string                 `protobuf:"bytes,1,opt,name=canvas_id,json=canvasId,proto3" json:"canvas_id,omitempty"`
+	LastSeq       uint64                 `protobuf:"varint,2,opt,name=last_seq,json=lastSeq,proto3" json:"last_seq,omitempty"`
+	Epoch         string                 `protobuf:"bytes,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *JoinRoom) Reset() {
 	*x = JoinRoom{}
-	mi := &file_websocket_msg_messages_proto_msgTypes[5]
+	mi := &file_websocket_msg_messages_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -302,7 +540,7 @@ func (x *JoinRoom) String() string {
 func (*JoinRoom) ProtoMessage() {}
 
 func (x *JoinRoom) ProtoReflect() protoreflect.Message {
-	mi := &file_websocket_msg_messages_proto_msgTypes[5]
+	mi := &file_websocket_msg_messages_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -315,7 +553,7 @@ func (x *JoinRoom) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JoinRoom.ProtoReflect.Descriptor instead.
 func (*JoinRoom) Descriptor() ([]byte, []int) {
-	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{5}
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *JoinRoom) GetCanvasId() string {
@@ -325,18 +563,33 @@ func (x *JoinRoom) GetCanvasId() string {
 	return ""
 }
 
+func (x *JoinRoom) GetLastSeq() uint64 {
+	if x != nil {
+		return x.LastSeq
+	}
+	return 0
+}
+
+func (x *JoinRoom) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
 type JoinRoomSuccess struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	CanvasId      string                 `protobuf:"bytes,1,opt,name=canvas_id,json=canvasId,proto3" json:"canvas_id,omitempty"`
 	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	ConnId        string                 `protobuf:"bytes,3,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	Epoch         string                 `protobuf:"bytes,4,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *JoinRoomSuccess) Reset() {
 	*x = JoinRoomSuccess{}
-	mi := &file_websocket_msg_messages_proto_msgTypes[6]
+	mi := &file_websocket_msg_messages_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -348,7 +601,7 @@ func (x *JoinRoomSuccess) String() string {
 func (*JoinRoomSuccess) ProtoMessage() {}
 
 func (x *JoinRoomSuccess) ProtoReflect() protoreflect.Message {
-	mi := &file_websocket_msg_messages_proto_msgTypes[6]
+	mi := &file_websocket_msg_messages_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -361,7 +614,7 @@ func (x *JoinRoomSuccess) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JoinRoomSuccess.ProtoReflect.Descriptor instead.
 func (*JoinRoomSuccess) Descriptor() ([]byte, []int) {
-	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{6}
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *JoinRoomSuccess) GetCanvasId() string {
@@ -385,32 +638,3050 @@ func (x *JoinRoomSuccess) GetConnId() string {
 	return ""
 }
 
-var File_websocket_msg_messages_proto protoreflect.FileDescriptor
+func (x *JoinRoomSuccess) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
 
-const file_websocket_msg_messages_proto_rawDesc = "" +
-	"\n" +
-	"\x1cwebsocket/msg/messages.proto\x12\x03msg\"9\n" +
-	"\tWSMessage\x12\x12\n" +
-	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
-	"\apayload\x18\x02 \x01(\fR\apayload\"\x1c\n" +
-	"\x04Auth\x12\x14\n" +
-	"\x05token\x18\x02 \x01(\tR\x05token\"\x1f\n" +
-	"\aWSError\x12\x14\n" +
-	"\x05error\x18\x01 \x01(\tR\x05error\"D\n" +
-	"\rMousePosition\x12\f\n" +
-	"\x01x\x18\x01 \x01(\rR\x01x\x12\f\n" +
-	"\x01y\x18\x02 \x01(\rR\x01y\x12\x17\n" +
-	"\aroom_id\x18\x03 \x01(\tR\x06roomId\"J\n" +
-	"\x13MousePositionUpdate\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\f\n" +
-	"\x01x\x18\x02 \x01(\rR\x01x\x12\f\n" +
-	"\x01y\x18\x03 \x01(\rR\x01y\"'\n" +
-	"\bJoinRoom\x12\x1b\n" +
-	"\tcanvas_id\x18\x01 \x01(\tR\bcanvasId\"`\n" +
-	"\x0fJoinRoomSuccess\x12\x1b\n" +
-	"\tcanvas_id\x18\x01 \x01(\tR\bcanvasId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x17\n" +
-	"\aconn_id\x18\x03 \x01(\tR\x06connIdB\x11Z\x0f./websocket;msgb\x06proto3"
+type PixelUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             uint32                 `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             uint32                 `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	R             uint32                 `protobuf:"varint,3,opt,name=r,proto3" json:"r,omitempty"`
+	G             uint32                 `protobuf:"varint,4,opt,name=g,proto3" json:"g,omitempty"`
+	B             uint32                 `protobuf:"varint,5,opt,name=b,proto3" json:"b,omitempty"`
+	A             uint32                 `protobuf:"varint,6,opt,name=a,proto3" json:"a,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PixelUpdate) Reset() {
+	*x = PixelUpdate{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PixelUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PixelUpdate) ProtoMessage() {}
+
+func (x *PixelUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PixelUpdate.ProtoReflect.Descriptor instead.
+func (*PixelUpdate) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PixelUpdate) GetX() uint32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *PixelUpdate) GetY() uint32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *PixelUpdate) GetR() uint32 {
+	if x != nil {
+		return x.R
+	}
+	return 0
+}
+
+func (x *PixelUpdate) GetG() uint32 {
+	if x != nil {
+		return x.G
+	}
+	return 0
+}
+
+func (x *PixelUpdate) GetB() uint32 {
+	if x != nil {
+		return x.B
+	}
+	return 0
+}
+
+func (x *PixelUpdate) GetA() uint32 {
+	if x != nil {
+		return x.A
+	}
+	return 0
+}
+
+type SetPixels struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Pixels        []*PixelUpdate         `protobuf:"bytes,2,rep,name=pixels,proto3" json:"pixels,omitempty"`
+	StrokeId      string                 `protobuf:"bytes,3,opt,name=stroke_id,json=strokeId,proto3" json:"stroke_id,omitempty"`
+	LayerId       string                 `protobuf:"bytes,4,opt,name=layer_id,json=layerId,proto3" json:"layer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPixels) Reset() {
+	*x = SetPixels{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPixels) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPixels) ProtoMessage() {}
+
+func (x *SetPixels) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPixels.ProtoReflect.Descriptor instead.
+func (*SetPixels) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SetPixels) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *SetPixels) GetPixels() []*PixelUpdate {
+	if x != nil {
+		return x.Pixels
+	}
+	return nil
+}
+
+func (x *SetPixels) GetStrokeId() string {
+	if x != nil {
+		return x.StrokeId
+	}
+	return ""
+}
+
+func (x *SetPixels) GetLayerId() string {
+	if x != nil {
+		return x.LayerId
+	}
+	return ""
+}
+
+type PixelsUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Pixels        []*PixelUpdate         `protobuf:"bytes,2,rep,name=pixels,proto3" json:"pixels,omitempty"`
+	Revision      uint64                 `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	LayerId       string                 `protobuf:"bytes,4,opt,name=layer_id,json=layerId,proto3" json:"layer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PixelsUpdate) Reset() {
+	*x = PixelsUpdate{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PixelsUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PixelsUpdate) ProtoMessage() {}
+
+func (x *PixelsUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PixelsUpdate.ProtoReflect.Descriptor instead.
+func (*PixelsUpdate) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PixelsUpdate) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PixelsUpdate) GetPixels() []*PixelUpdate {
+	if x != nil {
+		return x.Pixels
+	}
+	return nil
+}
+
+func (x *PixelsUpdate) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *PixelsUpdate) GetLayerId() string {
+	if x != nil {
+		return x.LayerId
+	}
+	return ""
+}
+
+type CanvasSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CanvasId      string                 `protobuf:"bytes,1,opt,name=canvas_id,json=canvasId,proto3" json:"canvas_id,omitempty"`
+	Width         uint32                 `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height        uint32                 `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	PixelData     []byte                 `protobuf:"bytes,4,opt,name=pixel_data,json=pixelData,proto3" json:"pixel_data,omitempty"`
+	Revision      uint64                 `protobuf:"varint,5,opt,name=revision,proto3" json:"revision,omitempty"`
+	Layers        []*Layer               `protobuf:"bytes,6,rep,name=layers,proto3" json:"layers,omitempty"`
+	Palette       []*Color               `protobuf:"bytes,7,rep,name=palette,proto3" json:"palette,omitempty"`
+	PaletteLocked bool                   `protobuf:"varint,8,opt,name=palette_locked,json=paletteLocked,proto3" json:"palette_locked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CanvasSnapshot) Reset() {
+	*x = CanvasSnapshot{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CanvasSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CanvasSnapshot) ProtoMessage() {}
+
+func (x *CanvasSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CanvasSnapshot.ProtoReflect.Descriptor instead.
+func (*CanvasSnapshot) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CanvasSnapshot) GetCanvasId() string {
+	if x != nil {
+		return x.CanvasId
+	}
+	return ""
+}
+
+func (x *CanvasSnapshot) GetWidth() uint32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *CanvasSnapshot) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *CanvasSnapshot) GetPixelData() []byte {
+	if x != nil {
+		return x.PixelData
+	}
+	return nil
+}
+
+func (x *CanvasSnapshot) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *CanvasSnapshot) GetLayers() []*Layer {
+	if x != nil {
+		return x.Layers
+	}
+	return nil
+}
+
+func (x *CanvasSnapshot) GetPalette() []*Color {
+	if x != nil {
+		return x.Palette
+	}
+	return nil
+}
+
+func (x *CanvasSnapshot) GetPaletteLocked() bool {
+	if x != nil {
+		return x.PaletteLocked
+	}
+	return false
+}
+
+type LeaveRoom struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveRoom) Reset() {
+	*x = LeaveRoom{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveRoom) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveRoom) ProtoMessage() {}
+
+func (x *LeaveRoom) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveRoom.ProtoReflect.Descriptor instead.
+func (*LeaveRoom) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LeaveRoom) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+type Participant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	AvatarUrl     string                 `protobuf:"bytes,3,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	CursorColor   string                 `protobuf:"bytes,4,opt,name=cursor_color,json=cursorColor,proto3" json:"cursor_color,omitempty"`
+	Connections   uint32                 `protobuf:"varint,5,opt,name=connections,proto3" json:"connections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Participant) Reset() {
+	*x = Participant{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Participant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Participant) ProtoMessage() {}
+
+func (x *Participant) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Participant.ProtoReflect.Descriptor instead.
+func (*Participant) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Participant) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Participant) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Participant) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *Participant) GetCursorColor() string {
+	if x != nil {
+		return x.CursorColor
+	}
+	return ""
+}
+
+func (x *Participant) GetConnections() uint32 {
+	if x != nil {
+		return x.Connections
+	}
+	return 0
+}
+
+type RoomParticipants struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Participants  []*Participant         `protobuf:"bytes,2,rep,name=participants,proto3" json:"participants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomParticipants) Reset() {
+	*x = RoomParticipants{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomParticipants) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomParticipants) ProtoMessage() {}
+
+func (x *RoomParticipants) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomParticipants.ProtoReflect.Descriptor instead.
+func (*RoomParticipants) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RoomParticipants) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *RoomParticipants) GetParticipants() []*Participant {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
+type UserJoined struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Participant   *Participant           `protobuf:"bytes,2,opt,name=participant,proto3" json:"participant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserJoined) Reset() {
+	*x = UserJoined{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserJoined) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserJoined) ProtoMessage() {}
+
+func (x *UserJoined) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserJoined.ProtoReflect.Descriptor instead.
+func (*UserJoined) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UserJoined) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *UserJoined) GetParticipant() *Participant {
+	if x != nil {
+		return x.Participant
+	}
+	return nil
+}
+
+type UserLeft struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserLeft) Reset() {
+	*x = UserLeft{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserLeft) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserLeft) ProtoMessage() {}
+
+func (x *UserLeft) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserLeft.ProtoReflect.Descriptor instead.
+func (*UserLeft) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UserLeft) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *UserLeft) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type AccessUpdated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	AccessRole    uint32                 `protobuf:"varint,2,opt,name=access_role,json=accessRole,proto3" json:"access_role,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessUpdated) Reset() {
+	*x = AccessUpdated{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessUpdated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessUpdated) ProtoMessage() {}
+
+func (x *AccessUpdated) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessUpdated.ProtoReflect.Descriptor instead.
+func (*AccessUpdated) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AccessUpdated) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *AccessUpdated) GetAccessRole() uint32 {
+	if x != nil {
+		return x.AccessRole
+	}
+	return 0
+}
+
+func (x *AccessUpdated) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type AccessRevoked struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessRevoked) Reset() {
+	*x = AccessRevoked{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessRevoked) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessRevoked) ProtoMessage() {}
+
+func (x *AccessRevoked) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessRevoked.ProtoReflect.Descriptor instead.
+func (*AccessRevoked) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AccessRevoked) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *AccessRevoked) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type Latency struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RttMs         uint32                 `protobuf:"varint,1,opt,name=rtt_ms,json=rttMs,proto3" json:"rtt_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Latency) Reset() {
+	*x = Latency{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Latency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Latency) ProtoMessage() {}
+
+func (x *Latency) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Latency.ProtoReflect.Descriptor instead.
+func (*Latency) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Latency) GetRttMs() uint32 {
+	if x != nil {
+		return x.RttMs
+	}
+	return 0
+}
+
+type OperationAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Seq           uint64                 `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationAck) Reset() {
+	*x = OperationAck{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationAck) ProtoMessage() {}
+
+func (x *OperationAck) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationAck.ProtoReflect.Descriptor instead.
+func (*OperationAck) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *OperationAck) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *OperationAck) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+type CaughtUp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	FromSeq       uint64                 `protobuf:"varint,2,opt,name=from_seq,json=fromSeq,proto3" json:"from_seq,omitempty"`
+	Revision      uint64                 `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaughtUp) Reset() {
+	*x = CaughtUp{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaughtUp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaughtUp) ProtoMessage() {}
+
+func (x *CaughtUp) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaughtUp.ProtoReflect.Descriptor instead.
+func (*CaughtUp) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CaughtUp) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *CaughtUp) GetFromSeq() uint64 {
+	if x != nil {
+		return x.FromSeq
+	}
+	return 0
+}
+
+func (x *CaughtUp) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type Undo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Undo) Reset() {
+	*x = Undo{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Undo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Undo) ProtoMessage() {}
+
+func (x *Undo) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Undo.ProtoReflect.Descriptor instead.
+func (*Undo) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Undo) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+type Redo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Redo) Reset() {
+	*x = Redo{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Redo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Redo) ProtoMessage() {}
+
+func (x *Redo) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Redo.ProtoReflect.Descriptor instead.
+func (*Redo) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Redo) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+type Layer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Position      uint32                 `protobuf:"varint,3,opt,name=position,proto3" json:"position,omitempty"`
+	Visible       bool                   `protobuf:"varint,4,opt,name=visible,proto3" json:"visible,omitempty"`
+	Opacity       uint32                 `protobuf:"varint,5,opt,name=opacity,proto3" json:"opacity,omitempty"`
+	Locked        bool                   `protobuf:"varint,6,opt,name=locked,proto3" json:"locked,omitempty"`
+	PixelData     []byte                 `protobuf:"bytes,7,opt,name=pixel_data,json=pixelData,proto3" json:"pixel_data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Layer) Reset() {
+	*x = Layer{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Layer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Layer) ProtoMessage() {}
+
+func (x *Layer) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Layer.ProtoReflect.Descriptor instead.
+func (*Layer) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Layer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Layer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Layer) GetPosition() uint32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *Layer) GetVisible() bool {
+	if x != nil {
+		return x.Visible
+	}
+	return false
+}
+
+func (x *Layer) GetOpacity() uint32 {
+	if x != nil {
+		return x.Opacity
+	}
+	return 0
+}
+
+func (x *Layer) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+func (x *Layer) GetPixelData() []byte {
+	if x != nil {
+		return x.PixelData
+	}
+	return nil
+}
+
+type LayersUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Layers        []*Layer               `protobuf:"bytes,2,rep,name=layers,proto3" json:"layers,omitempty"`
+	Revision      uint64                 `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LayersUpdate) Reset() {
+	*x = LayersUpdate{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LayersUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LayersUpdate) ProtoMessage() {}
+
+func (x *LayersUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LayersUpdate.ProtoReflect.Descriptor instead.
+func (*LayersUpdate) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *LayersUpdate) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *LayersUpdate) GetLayers() []*Layer {
+	if x != nil {
+		return x.Layers
+	}
+	return nil
+}
+
+func (x *LayersUpdate) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type Point struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             int32                  `protobuf:"zigzag32,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"zigzag32,2,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Point) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Point) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type Color struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	R             uint32                 `protobuf:"varint,1,opt,name=r,proto3" json:"r,omitempty"`
+	G             uint32                 `protobuf:"varint,2,opt,name=g,proto3" json:"g,omitempty"`
+	B             uint32                 `protobuf:"varint,3,opt,name=b,proto3" json:"b,omitempty"`
+	A             uint32                 `protobuf:"varint,4,opt,name=a,proto3" json:"a,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Color) Reset() {
+	*x = Color{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Color) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Color) ProtoMessage() {}
+
+func (x *Color) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Color.ProtoReflect.Descriptor instead.
+func (*Color) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Color) GetR() uint32 {
+	if x != nil {
+		return x.R
+	}
+	return 0
+}
+
+func (x *Color) GetG() uint32 {
+	if x != nil {
+		return x.G
+	}
+	return 0
+}
+
+func (x *Color) GetB() uint32 {
+	if x != nil {
+		return x.B
+	}
+	return 0
+}
+
+func (x *Color) GetA() uint32 {
+	if x != nil {
+		return x.A
+	}
+	return 0
+}
+
+type FloodFill struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	LayerId       string                 `protobuf:"bytes,2,opt,name=layer_id,json=layerId,proto3" json:"layer_id,omitempty"`
+	StrokeId      string                 `protobuf:"bytes,3,opt,name=stroke_id,json=strokeId,proto3" json:"stroke_id,omitempty"`
+	X             uint32                 `protobuf:"varint,4,opt,name=x,proto3" json:"x,omitempty"`
+	Y             uint32                 `protobuf:"varint,5,opt,name=y,proto3" json:"y,omitempty"`
+	Color         *Color                 `protobuf:"bytes,6,opt,name=color,proto3" json:"color,omitempty"`
+	Tolerance     uint32                 `protobuf:"varint,7,opt,name=tolerance,proto3" json:"tolerance,omitempty"`
+	Contiguous    bool                   `protobuf:"varint,8,opt,name=contiguous,proto3" json:"contiguous,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FloodFill) Reset() {
+	*x = FloodFill{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FloodFill) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FloodFill) ProtoMessage() {}
+
+func (x *FloodFill) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FloodFill.ProtoReflect.Descriptor instead.
+func (*FloodFill) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *FloodFill) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *FloodFill) GetLayerId() string {
+	if x != nil {
+		return x.LayerId
+	}
+	return ""
+}
+
+func (x *FloodFill) GetStrokeId() string {
+	if x != nil {
+		return x.StrokeId
+	}
+	return ""
+}
+
+func (x *FloodFill) GetX() uint32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *FloodFill) GetY() uint32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *FloodFill) GetColor() *Color {
+	if x != nil {
+		return x.Color
+	}
+	return nil
+}
+
+func (x *FloodFill) GetTolerance() uint32 {
+	if x != nil {
+		return x.Tolerance
+	}
+	return 0
+}
+
+func (x *FloodFill) GetContiguous() bool {
+	if x != nil {
+		return x.Contiguous
+	}
+	return false
+}
+
+type DrawLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	LayerId       string                 `protobuf:"bytes,2,opt,name=layer_id,json=layerId,proto3" json:"layer_id,omitempty"`
+	StrokeId      string                 `protobuf:"bytes,3,opt,name=stroke_id,json=strokeId,proto3" json:"stroke_id,omitempty"`
+	From          *Point                 `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	To            *Point                 `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`
+	Color         *Color                 `protobuf:"bytes,6,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrawLine) Reset() {
+	*x = DrawLine{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrawLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrawLine) ProtoMessage() {}
+
+func (x *DrawLine) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrawLine.ProtoReflect.Descriptor instead.
+func (*DrawLine) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DrawLine) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *DrawLine) GetLayerId() string {
+	if x != nil {
+		return x.LayerId
+	}
+	return ""
+}
+
+func (x *DrawLine) GetStrokeId() string {
+	if x != nil {
+		return x.StrokeId
+	}
+	return ""
+}
+
+func (x *DrawLine) GetFrom() *Point {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *DrawLine) GetTo() *Point {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *DrawLine) GetColor() *Color {
+	if x != nil {
+		return x.Color
+	}
+	return nil
+}
+
+type DrawShape struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	LayerId       string                 `protobuf:"bytes,2,opt,name=layer_id,json=layerId,proto3" json:"layer_id,omitempty"`
+	StrokeId      string                 `protobuf:"bytes,3,opt,name=stroke_id,json=strokeId,proto3" json:"stroke_id,omitempty"`
+	Shape         ShapeType              `protobuf:"varint,4,opt,name=shape,proto3,enum=msg.ShapeType" json:"shape,omitempty"`
+	From          *Point                 `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`
+	To            *Point                 `protobuf:"bytes,6,opt,name=to,proto3" json:"to,omitempty"`
+	Color         *Color                 `protobuf:"bytes,7,opt,name=color,proto3" json:"color,omitempty"`
+	Filled        bool                   `protobuf:"varint,8,opt,name=filled,proto3" json:"filled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrawShape) Reset() {
+	*x = DrawShape{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrawShape) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrawShape) ProtoMessage() {}
+
+func (x *DrawShape) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrawShape.ProtoReflect.Descriptor instead.
+func (*DrawShape) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DrawShape) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *DrawShape) GetLayerId() string {
+	if x != nil {
+		return x.LayerId
+	}
+	return ""
+}
+
+func (x *DrawShape) GetStrokeId() string {
+	if x != nil {
+		return x.StrokeId
+	}
+	return ""
+}
+
+func (x *DrawShape) GetShape() ShapeType {
+	if x != nil {
+		return x.Shape
+	}
+	return ShapeType_RECTANGLE
+}
+
+func (x *DrawShape) GetFrom() *Point {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *DrawShape) GetTo() *Point {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *DrawShape) GetColor() *Color {
+	if x != nil {
+		return x.Color
+	}
+	return nil
+}
+
+func (x *DrawShape) GetFilled() bool {
+	if x != nil {
+		return x.Filled
+	}
+	return false
+}
+
+type BrushStamp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	LayerId       string                 `protobuf:"bytes,2,opt,name=layer_id,json=layerId,proto3" json:"layer_id,omitempty"`
+	StrokeId      string                 `protobuf:"bytes,3,opt,name=stroke_id,json=strokeId,proto3" json:"stroke_id,omitempty"`
+	Points        []*Point               `protobuf:"bytes,4,rep,name=points,proto3" json:"points,omitempty"`
+	Size          uint32                 `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	Shape         BrushShape             `protobuf:"varint,6,opt,name=shape,proto3,enum=msg.BrushShape" json:"shape,omitempty"`
+	Color         *Color                 `protobuf:"bytes,7,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BrushStamp) Reset() {
+	*x = BrushStamp{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BrushStamp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BrushStamp) ProtoMessage() {}
+
+func (x *BrushStamp) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BrushStamp.ProtoReflect.Descriptor instead.
+func (*BrushStamp) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *BrushStamp) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *BrushStamp) GetLayerId() string {
+	if x != nil {
+		return x.LayerId
+	}
+	return ""
+}
+
+func (x *BrushStamp) GetStrokeId() string {
+	if x != nil {
+		return x.StrokeId
+	}
+	return ""
+}
+
+func (x *BrushStamp) GetPoints() []*Point {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+func (x *BrushStamp) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *BrushStamp) GetShape() BrushShape {
+	if x != nil {
+		return x.Shape
+	}
+	return BrushShape_SQUARE
+}
+
+func (x *BrushStamp) GetColor() *Color {
+	if x != nil {
+		return x.Color
+	}
+	return nil
+}
+
+type Rect struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             int32                  `protobuf:"zigzag32,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"zigzag32,2,opt,name=y,proto3" json:"y,omitempty"`
+	Width         uint32                 `protobuf:"varint,3,opt,name=width,proto3" json:"width,omitempty"`
+	Height        uint32                 `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rect) Reset() {
+	*x = Rect{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rect) ProtoMessage() {}
+
+func (x *Rect) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rect.ProtoReflect.Descriptor instead.
+func (*Rect) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *Rect) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Rect) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *Rect) GetWidth() uint32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Rect) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+type Transform struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	FlipHorizontal bool                   `protobuf:"varint,1,opt,name=flip_horizontal,json=flipHorizontal,proto3" json:"flip_horizontal,omitempty"`
+	FlipVertical   bool                   `protobuf:"varint,2,opt,name=flip_vertical,json=flipVertical,proto3" json:"flip_vertical,omitempty"`
+	Rotation       uint32                 `protobuf:"varint,3,opt,name=rotation,proto3" json:"rotation,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Transform) Reset() {
+	*x = Transform{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transform) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transform) ProtoMessage() {}
+
+func (x *Transform) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transform.ProtoReflect.Descriptor instead.
+func (*Transform) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *Transform) GetFlipHorizontal() bool {
+	if x != nil {
+		return x.FlipHorizontal
+	}
+	return false
+}
+
+func (x *Transform) GetFlipVertical() bool {
+	if x != nil {
+		return x.FlipVertical
+	}
+	return false
+}
+
+func (x *Transform) GetRotation() uint32 {
+	if x != nil {
+		return x.Rotation
+	}
+	return 0
+}
+
+type Selection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	LayerId       string                 `protobuf:"bytes,2,opt,name=layer_id,json=layerId,proto3" json:"layer_id,omitempty"`
+	Rect          *Rect                  `protobuf:"bytes,3,opt,name=rect,proto3" json:"rect,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Selection) Reset() {
+	*x = Selection{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Selection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Selection) ProtoMessage() {}
+
+func (x *Selection) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Selection.ProtoReflect.Descriptor instead.
+func (*Selection) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *Selection) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *Selection) GetLayerId() string {
+	if x != nil {
+		return x.LayerId
+	}
+	return ""
+}
+
+func (x *Selection) GetRect() *Rect {
+	if x != nil {
+		return x.Rect
+	}
+	return nil
+}
+
+type SelectionUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ConnId        string                 `protobuf:"bytes,3,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	LayerId       string                 `protobuf:"bytes,4,opt,name=layer_id,json=layerId,proto3" json:"layer_id,omitempty"`
+	Rect          *Rect                  `protobuf:"bytes,5,opt,name=rect,proto3" json:"rect,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelectionUpdate) Reset() {
+	*x = SelectionUpdate{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectionUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectionUpdate) ProtoMessage() {}
+
+func (x *SelectionUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectionUpdate.ProtoReflect.Descriptor instead.
+func (*SelectionUpdate) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SelectionUpdate) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *SelectionUpdate) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SelectionUpdate) GetConnId() string {
+	if x != nil {
+		return x.ConnId
+	}
+	return ""
+}
+
+func (x *SelectionUpdate) GetLayerId() string {
+	if x != nil {
+		return x.LayerId
+	}
+	return ""
+}
+
+func (x *SelectionUpdate) GetRect() *Rect {
+	if x != nil {
+		return x.Rect
+	}
+	return nil
+}
+
+type SelectionEdit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	LayerId       string                 `protobuf:"bytes,2,opt,name=layer_id,json=layerId,proto3" json:"layer_id,omitempty"`
+	StrokeId      string                 `protobuf:"bytes,3,opt,name=stroke_id,json=strokeId,proto3" json:"stroke_id,omitempty"`
+	Rect          *Rect                  `protobuf:"bytes,4,opt,name=rect,proto3" json:"rect,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelectionEdit) Reset() {
+	*x = SelectionEdit{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectionEdit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectionEdit) ProtoMessage() {}
+
+func (x *SelectionEdit) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectionEdit.ProtoReflect.Descriptor instead.
+func (*SelectionEdit) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SelectionEdit) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *SelectionEdit) GetLayerId() string {
+	if x != nil {
+		return x.LayerId
+	}
+	return ""
+}
+
+func (x *SelectionEdit) GetStrokeId() string {
+	if x != nil {
+		return x.StrokeId
+	}
+	return ""
+}
+
+func (x *SelectionEdit) GetRect() *Rect {
+	if x != nil {
+		return x.Rect
+	}
+	return nil
+}
+
+type ClipboardUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Width         uint32                 `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Height        uint32                 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClipboardUpdate) Reset() {
+	*x = ClipboardUpdate{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClipboardUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClipboardUpdate) ProtoMessage() {}
+
+func (x *ClipboardUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClipboardUpdate.ProtoReflect.Descriptor instead.
+func (*ClipboardUpdate) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ClipboardUpdate) GetWidth() uint32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *ClipboardUpdate) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+type Paste struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	LayerId       string                 `protobuf:"bytes,2,opt,name=layer_id,json=layerId,proto3" json:"layer_id,omitempty"`
+	StrokeId      string                 `protobuf:"bytes,3,opt,name=stroke_id,json=strokeId,proto3" json:"stroke_id,omitempty"`
+	Position      *Point                 `protobuf:"bytes,4,opt,name=position,proto3" json:"position,omitempty"`
+	Transform     *Transform             `protobuf:"bytes,5,opt,name=transform,proto3" json:"transform,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Paste) Reset() {
+	*x = Paste{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Paste) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Paste) ProtoMessage() {}
+
+func (x *Paste) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Paste.ProtoReflect.Descriptor instead.
+func (*Paste) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *Paste) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *Paste) GetLayerId() string {
+	if x != nil {
+		return x.LayerId
+	}
+	return ""
+}
+
+func (x *Paste) GetStrokeId() string {
+	if x != nil {
+		return x.StrokeId
+	}
+	return ""
+}
+
+func (x *Paste) GetPosition() *Point {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *Paste) GetTransform() *Transform {
+	if x != nil {
+		return x.Transform
+	}
+	return nil
+}
+
+type MoveSelection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	LayerId       string                 `protobuf:"bytes,2,opt,name=layer_id,json=layerId,proto3" json:"layer_id,omitempty"`
+	StrokeId      string                 `protobuf:"bytes,3,opt,name=stroke_id,json=strokeId,proto3" json:"stroke_id,omitempty"`
+	Rect          *Rect                  `protobuf:"bytes,4,opt,name=rect,proto3" json:"rect,omitempty"`
+	Position      *Point                 `protobuf:"bytes,5,opt,name=position,proto3" json:"position,omitempty"`
+	Transform     *Transform             `protobuf:"bytes,6,opt,name=transform,proto3" json:"transform,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveSelection) Reset() {
+	*x = MoveSelection{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveSelection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveSelection) ProtoMessage() {}
+
+func (x *MoveSelection) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveSelection.ProtoReflect.Descriptor instead.
+func (*MoveSelection) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *MoveSelection) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *MoveSelection) GetLayerId() string {
+	if x != nil {
+		return x.LayerId
+	}
+	return ""
+}
+
+func (x *MoveSelection) GetStrokeId() string {
+	if x != nil {
+		return x.StrokeId
+	}
+	return ""
+}
+
+func (x *MoveSelection) GetRect() *Rect {
+	if x != nil {
+		return x.Rect
+	}
+	return nil
+}
+
+func (x *MoveSelection) GetPosition() *Point {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *MoveSelection) GetTransform() *Transform {
+	if x != nil {
+		return x.Transform
+	}
+	return nil
+}
+
+type PaletteEdit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Action        PaletteAction          `protobuf:"varint,2,opt,name=action,proto3,enum=msg.PaletteAction" json:"action,omitempty"`
+	Index         uint32                 `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	Color         *Color                 `protobuf:"bytes,4,opt,name=color,proto3" json:"color,omitempty"`
+	Colors        []*Color               `protobuf:"bytes,5,rep,name=colors,proto3" json:"colors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaletteEdit) Reset() {
+	*x = PaletteEdit{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaletteEdit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaletteEdit) ProtoMessage() {}
+
+func (x *PaletteEdit) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaletteEdit.ProtoReflect.Descriptor instead.
+func (*PaletteEdit) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *PaletteEdit) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *PaletteEdit) GetAction() PaletteAction {
+	if x != nil {
+		return x.Action
+	}
+	return PaletteAction_ADD_COLOR
+}
+
+func (x *PaletteEdit) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *PaletteEdit) GetColor() *Color {
+	if x != nil {
+		return x.Color
+	}
+	return nil
+}
+
+func (x *PaletteEdit) GetColors() []*Color {
+	if x != nil {
+		return x.Colors
+	}
+	return nil
+}
+
+type PaletteUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Colors        []*Color               `protobuf:"bytes,2,rep,name=colors,proto3" json:"colors,omitempty"`
+	Locked        bool                   `protobuf:"varint,3,opt,name=locked,proto3" json:"locked,omitempty"`
+	Revision      uint64                 `protobuf:"varint,4,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaletteUpdate) Reset() {
+	*x = PaletteUpdate{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaletteUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaletteUpdate) ProtoMessage() {}
+
+func (x *PaletteUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaletteUpdate.ProtoReflect.Descriptor instead.
+func (*PaletteUpdate) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *PaletteUpdate) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *PaletteUpdate) GetColors() []*Color {
+	if x != nil {
+		return x.Colors
+	}
+	return nil
+}
+
+func (x *PaletteUpdate) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+func (x *PaletteUpdate) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type ChatMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMessage) Reset() {
+	*x = ChatMessage{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessage) ProtoMessage() {}
+
+func (x *ChatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
+func (*ChatMessage) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ChatMessage) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type ChatMessageReceived struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	AvatarUrl     string                 `protobuf:"bytes,5,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	Content       string                 `protobuf:"bytes,6,opt,name=content,proto3" json:"content,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMessageReceived) Reset() {
+	*x = ChatMessageReceived{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMessageReceived) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessageReceived) ProtoMessage() {}
+
+func (x *ChatMessageReceived) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMessageReceived.ProtoReflect.Descriptor instead.
+func (*ChatMessageReceived) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ChatMessageReceived) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ChatMessageReceived) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ChatMessageReceived) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ChatMessageReceived) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ChatMessageReceived) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *ChatMessageReceived) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ChatMessageReceived) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type ChatSettings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	ViewerChat    bool                   `protobuf:"varint,2,opt,name=viewer_chat,json=viewerChat,proto3" json:"viewer_chat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatSettings) Reset() {
+	*x = ChatSettings{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatSettings) ProtoMessage() {}
+
+func (x *ChatSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatSettings.ProtoReflect.Descriptor instead.
+func (*ChatSettings) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ChatSettings) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ChatSettings) GetViewerChat() bool {
+	if x != nil {
+		return x.ViewerChat
+	}
+	return false
+}
+
+type Comment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	ParentId      string                 `protobuf:"bytes,3,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,5,opt,name=username,proto3" json:"username,omitempty"`
+	AvatarUrl     string                 `protobuf:"bytes,6,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	Content       string                 `protobuf:"bytes,7,opt,name=content,proto3" json:"content,omitempty"`
+	Anchor        *Rect                  `protobuf:"bytes,8,opt,name=anchor,proto3" json:"anchor,omitempty"`
+	Resolved      bool                   `protobuf:"varint,9,opt,name=resolved,proto3" json:"resolved,omitempty"`
+	ResolvedBy    string                 `protobuf:"bytes,10,opt,name=resolved_by,json=resolvedBy,proto3" json:"resolved_by,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *Comment) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *Comment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Comment) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Comment) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Comment) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Comment) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *Comment) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Comment) GetAnchor() *Rect {
+	if x != nil {
+		return x.Anchor
+	}
+	return nil
+}
+
+func (x *Comment) GetResolved() bool {
+	if x != nil {
+		return x.Resolved
+	}
+	return false
+}
+
+func (x *Comment) GetResolvedBy() string {
+	if x != nil {
+		return x.ResolvedBy
+	}
+	return ""
+}
+
+func (x *Comment) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Comment) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type CommentDeleted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	CommentId     string                 `protobuf:"bytes,2,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	ParentId      string                 `protobuf:"bytes,3,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommentDeleted) Reset() {
+	*x = CommentDeleted{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommentDeleted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommentDeleted) ProtoMessage() {}
+
+func (x *CommentDeleted) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommentDeleted.ProtoReflect.Descriptor instead.
+func (*CommentDeleted) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *CommentDeleted) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *CommentDeleted) GetCommentId() string {
+	if x != nil {
+		return x.CommentId
+	}
+	return ""
+}
+
+func (x *CommentDeleted) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+type VersionRestored struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	VersionId     string                 `protobuf:"bytes,2,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
+	RestoredBy    string                 `protobuf:"bytes,3,opt,name=restored_by,json=restoredBy,proto3" json:"restored_by,omitempty"`
+	Revision      uint64                 `protobuf:"varint,4,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VersionRestored) Reset() {
+	*x = VersionRestored{}
+	mi := &file_websocket_msg_messages_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionRestored) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionRestored) ProtoMessage() {}
+
+func (x *VersionRestored) ProtoReflect() protoreflect.Message {
+	mi := &file_websocket_msg_messages_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionRestored.ProtoReflect.Descriptor instead.
+func (*VersionRestored) Descriptor() ([]byte, []int) {
+	return file_websocket_msg_messages_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *VersionRestored) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *VersionRestored) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+func (x *VersionRestored) GetRestoredBy() string {
+	if x != nil {
+		return x.RestoredBy
+	}
+	return ""
+}
+
+func (x *VersionRestored) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+var File_websocket_msg_messages_proto protoreflect.FileDescriptor
+
+const file_websocket_msg_messages_proto_rawDesc = "" +
+	"\n" +
+	"\x1cwebsocket/msg/messages.proto\x12\x03msg\"9\n" +
+	"\tWSMessage\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\apayload\x18\x02 \x01(\fR\apayload\"\x1c\n" +
+	"\x04Auth\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"\x1f\n" +
+	"\aWSError\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\"z\n" +
+	"\rMousePosition\x12\f\n" +
+	"\x01x\x18\x01 \x01(\rR\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\rR\x01y\x12\x17\n" +
+	"\aroom_id\x18\x03 \x01(\tR\x06roomId\x12\x12\n" +
+	"\x04tool\x18\x04 \x01(\tR\x04tool\x12 \n" +
+	"\x05color\x18\x05 \x01(\v2\n" +
+	".msg.ColorR\x05color\"\x99\x01\n" +
+	"\x13MousePositionUpdate\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\f\n" +
+	"\x01x\x18\x02 \x01(\rR\x01x\x12\f\n" +
+	"\x01y\x18\x03 \x01(\rR\x01y\x12\x17\n" +
+	"\aconn_id\x18\x04 \x01(\tR\x06connId\x12\x12\n" +
+	"\x04tool\x18\x05 \x01(\tR\x04tool\x12 \n" +
+	"\x05color\x18\x06 \x01(\v2\n" +
+	".msg.ColorR\x05color\"Z\n" +
+	"\vCursorBatch\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x122\n" +
+	"\acursors\x18\x02 \x03(\v2\x18.msg.MousePositionUpdateR\acursors\"X\n" +
+	"\bJoinRoom\x12\x1b\n" +
+	"\tcanvas_id\x18\x01 \x01(\tR\bcanvasId\x12\x19\n" +
+	"\blast_seq\x18\x02 \x01(\x04R\alastSeq\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\tR\x05epoch\"v\n" +
+	"\x0fJoinRoomSuccess\x12\x1b\n" +
+	"\tcanvas_id\x18\x01 \x01(\tR\bcanvasId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x17\n" +
+	"\aconn_id\x18\x03 \x01(\tR\x06connId\x12\x14\n" +
+	"\x05epoch\x18\x04 \x01(\tR\x05epoch\"a\n" +
+	"\vPixelUpdate\x12\f\n" +
+	"\x01x\x18\x01 \x01(\rR\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\rR\x01y\x12\f\n" +
+	"\x01r\x18\x03 \x01(\rR\x01r\x12\f\n" +
+	"\x01g\x18\x04 \x01(\rR\x01g\x12\f\n" +
+	"\x01b\x18\x05 \x01(\rR\x01b\x12\f\n" +
+	"\x01a\x18\x06 \x01(\rR\x01a\"\x86\x01\n" +
+	"\tSetPixels\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12(\n" +
+	"\x06pixels\x18\x02 \x03(\v2\x10.msg.PixelUpdateR\x06pixels\x12\x1b\n" +
+	"\tstroke_id\x18\x03 \x01(\tR\bstrokeId\x12\x19\n" +
+	"\blayer_id\x18\x04 \x01(\tR\alayerId\"\x88\x01\n" +
+	"\fPixelsUpdate\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12(\n" +
+	"\x06pixels\x18\x02 \x03(\v2\x10.msg.PixelUpdateR\x06pixels\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x04R\brevision\x12\x19\n" +
+	"\blayer_id\x18\x04 \x01(\tR\alayerId\"\x87\x02\n" +
+	"\x0eCanvasSnapshot\x12\x1b\n" +
+	"\tcanvas_id\x18\x01 \x01(\tR\bcanvasId\x12\x14\n" +
+	"\x05width\x18\x02 \x01(\rR\x05width\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\rR\x06height\x12\x1d\n" +
+	"\n" +
+	"pixel_data\x18\x04 \x01(\fR\tpixelData\x12\x1a\n" +
+	"\brevision\x18\x05 \x01(\x04R\brevision\x12\"\n" +
+	"\x06layers\x18\x06 \x03(\v2\n" +
+	".msg.LayerR\x06layers\x12$\n" +
+	"\apalette\x18\a \x03(\v2\n" +
+	".msg.ColorR\apalette\x12%\n" +
+	"\x0epalette_locked\x18\b \x01(\bR\rpaletteLocked\"$\n" +
+	"\tLeaveRoom\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\"\xa6\x01\n" +
+	"\vParticipant\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x03 \x01(\tR\tavatarUrl\x12!\n" +
+	"\fcursor_color\x18\x04 \x01(\tR\vcursorColor\x12 \n" +
+	"\vconnections\x18\x05 \x01(\rR\vconnections\"a\n" +
+	"\x10RoomParticipants\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x124\n" +
+	"\fparticipants\x18\x02 \x03(\v2\x10.msg.ParticipantR\fparticipants\"Y\n" +
+	"\n" +
+	"UserJoined\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x122\n" +
+	"\vparticipant\x18\x02 \x01(\v2\x10.msg.ParticipantR\vparticipant\"<\n" +
+	"\bUserLeft\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"a\n" +
+	"\rAccessUpdated\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1f\n" +
+	"\vaccess_role\x18\x02 \x01(\rR\n" +
+	"accessRole\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"@\n" +
+	"\rAccessRevoked\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\" \n" +
+	"\aLatency\x12\x15\n" +
+	"\x06rtt_ms\x18\x01 \x01(\rR\x05rttMs\"9\n" +
+	"\fOperationAck\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x04R\x03seq\"Z\n" +
+	"\bCaughtUp\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x19\n" +
+	"\bfrom_seq\x18\x02 \x01(\x04R\afromSeq\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x04R\brevision\"\x1f\n" +
+	"\x04Undo\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\"\x1f\n" +
+	"\x04Redo\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\"\xb2\x01\n" +
+	"\x05Layer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bposition\x18\x03 \x01(\rR\bposition\x12\x18\n" +
+	"\avisible\x18\x04 \x01(\bR\avisible\x12\x18\n" +
+	"\aopacity\x18\x05 \x01(\rR\aopacity\x12\x16\n" +
+	"\x06locked\x18\x06 \x01(\bR\x06locked\x12\x1d\n" +
+	"\n" +
+	"pixel_data\x18\a \x01(\fR\tpixelData\"g\n" +
+	"\fLayersUpdate\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\"\n" +
+	"\x06layers\x18\x02 \x03(\v2\n" +
+	".msg.LayerR\x06layers\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x04R\brevision\"#\n" +
+	"\x05Point\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x11R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x11R\x01y\"?\n" +
+	"\x05Color\x12\f\n" +
+	"\x01r\x18\x01 \x01(\rR\x01r\x12\f\n" +
+	"\x01g\x18\x02 \x01(\rR\x01g\x12\f\n" +
+	"\x01b\x18\x03 \x01(\rR\x01b\x12\f\n" +
+	"\x01a\x18\x04 \x01(\rR\x01a\"\xd8\x01\n" +
+	"\tFloodFill\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x19\n" +
+	"\blayer_id\x18\x02 \x01(\tR\alayerId\x12\x1b\n" +
+	"\tstroke_id\x18\x03 \x01(\tR\bstrokeId\x12\f\n" +
+	"\x01x\x18\x04 \x01(\rR\x01x\x12\f\n" +
+	"\x01y\x18\x05 \x01(\rR\x01y\x12 \n" +
+	"\x05color\x18\x06 \x01(\v2\n" +
+	".msg.ColorR\x05color\x12\x1c\n" +
+	"\ttolerance\x18\a \x01(\rR\ttolerance\x12\x1e\n" +
+	"\n" +
+	"contiguous\x18\b \x01(\bR\n" +
+	"contiguous\"\xb9\x01\n" +
+	"\bDrawLine\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x19\n" +
+	"\blayer_id\x18\x02 \x01(\tR\alayerId\x12\x1b\n" +
+	"\tstroke_id\x18\x03 \x01(\tR\bstrokeId\x12\x1e\n" +
+	"\x04from\x18\x04 \x01(\v2\n" +
+	".msg.PointR\x04from\x12\x1a\n" +
+	"\x02to\x18\x05 \x01(\v2\n" +
+	".msg.PointR\x02to\x12 \n" +
+	"\x05color\x18\x06 \x01(\v2\n" +
+	".msg.ColorR\x05color\"\xf8\x01\n" +
+	"\tDrawShape\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x19\n" +
+	"\blayer_id\x18\x02 \x01(\tR\alayerId\x12\x1b\n" +
+	"\tstroke_id\x18\x03 \x01(\tR\bstrokeId\x12$\n" +
+	"\x05shape\x18\x04 \x01(\x0e2\x0e.msg.ShapeTypeR\x05shape\x12\x1e\n" +
+	"\x04from\x18\x05 \x01(\v2\n" +
+	".msg.PointR\x04from\x12\x1a\n" +
+	"\x02to\x18\x06 \x01(\v2\n" +
+	".msg.PointR\x02to\x12 \n" +
+	"\x05color\x18\a \x01(\v2\n" +
+	".msg.ColorR\x05color\x12\x16\n" +
+	"\x06filled\x18\b \x01(\bR\x06filled\"\xde\x01\n" +
+	"\n" +
+	"BrushStamp\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x19\n" +
+	"\blayer_id\x18\x02 \x01(\tR\alayerId\x12\x1b\n" +
+	"\tstroke_id\x18\x03 \x01(\tR\bstrokeId\x12\"\n" +
+	"\x06points\x18\x04 \x03(\v2\n" +
+	".msg.PointR\x06points\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\rR\x04size\x12%\n" +
+	"\x05shape\x18\x06 \x01(\x0e2\x0f.msg.BrushShapeR\x05shape\x12 \n" +
+	"\x05color\x18\a \x01(\v2\n" +
+	".msg.ColorR\x05color\"P\n" +
+	"\x04Rect\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x11R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x11R\x01y\x12\x14\n" +
+	"\x05width\x18\x03 \x01(\rR\x05width\x12\x16\n" +
+	"\x06height\x18\x04 \x01(\rR\x06height\"u\n" +
+	"\tTransform\x12'\n" +
+	"\x0fflip_horizontal\x18\x01 \x01(\bR\x0eflipHorizontal\x12#\n" +
+	"\rflip_vertical\x18\x02 \x01(\bR\fflipVertical\x12\x1a\n" +
+	"\brotation\x18\x03 \x01(\rR\brotation\"^\n" +
+	"\tSelection\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x19\n" +
+	"\blayer_id\x18\x02 \x01(\tR\alayerId\x12\x1d\n" +
+	"\x04rect\x18\x03 \x01(\v2\t.msg.RectR\x04rect\"\x96\x01\n" +
+	"\x0fSelectionUpdate\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x17\n" +
+	"\aconn_id\x18\x03 \x01(\tR\x06connId\x12\x19\n" +
+	"\blayer_id\x18\x04 \x01(\tR\alayerId\x12\x1d\n" +
+	"\x04rect\x18\x05 \x01(\v2\t.msg.RectR\x04rect\"\x7f\n" +
+	"\rSelectionEdit\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x19\n" +
+	"\blayer_id\x18\x02 \x01(\tR\alayerId\x12\x1b\n" +
+	"\tstroke_id\x18\x03 \x01(\tR\bstrokeId\x12\x1d\n" +
+	"\x04rect\x18\x04 \x01(\v2\t.msg.RectR\x04rect\"?\n" +
+	"\x0fClipboardUpdate\x12\x14\n" +
+	"\x05width\x18\x01 \x01(\rR\x05width\x12\x16\n" +
+	"\x06height\x18\x02 \x01(\rR\x06height\"\xae\x01\n" +
+	"\x05Paste\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x19\n" +
+	"\blayer_id\x18\x02 \x01(\tR\alayerId\x12\x1b\n" +
+	"\tstroke_id\x18\x03 \x01(\tR\bstrokeId\x12&\n" +
+	"\bposition\x18\x04 \x01(\v2\n" +
+	".msg.PointR\bposition\x12,\n" +
+	"\ttransform\x18\x05 \x01(\v2\x0e.msg.TransformR\ttransform\"\xd5\x01\n" +
+	"\rMoveSelection\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x19\n" +
+	"\blayer_id\x18\x02 \x01(\tR\alayerId\x12\x1b\n" +
+	"\tstroke_id\x18\x03 \x01(\tR\bstrokeId\x12\x1d\n" +
+	"\x04rect\x18\x04 \x01(\v2\t.msg.RectR\x04rect\x12&\n" +
+	"\bposition\x18\x05 \x01(\v2\n" +
+	".msg.PointR\bposition\x12,\n" +
+	"\ttransform\x18\x06 \x01(\v2\x0e.msg.TransformR\ttransform\"\xae\x01\n" +
+	"\vPaletteEdit\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12*\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x12.msg.PaletteActionR\x06action\x12\x14\n" +
+	"\x05index\x18\x03 \x01(\rR\x05index\x12 \n" +
+	"\x05color\x18\x04 \x01(\v2\n" +
+	".msg.ColorR\x05color\x12\"\n" +
+	"\x06colors\x18\x05 \x03(\v2\n" +
+	".msg.ColorR\x06colors\"\x80\x01\n" +
+	"\rPaletteUpdate\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\"\n" +
+	"\x06colors\x18\x02 \x03(\v2\n" +
+	".msg.ColorR\x06colors\x12\x16\n" +
+	"\x06locked\x18\x03 \x01(\bR\x06locked\x12\x1a\n" +
+	"\brevision\x18\x04 \x01(\x04R\brevision\"@\n" +
+	"\vChatMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\"\xda\x01\n" +
+	"\x13ChatMessageReceived\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x04 \x01(\tR\busername\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x05 \x01(\tR\tavatarUrl\x12\x18\n" +
+	"\acontent\x18\x06 \x01(\tR\acontent\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\"H\n" +
+	"\fChatSettings\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1f\n" +
+	"\vviewer_chat\x18\x02 \x01(\bR\n" +
+	"viewerChat\"\xdb\x02\n" +
+	"\aComment\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x1b\n" +
+	"\tparent_id\x18\x03 \x01(\tR\bparentId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x05 \x01(\tR\busername\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x06 \x01(\tR\tavatarUrl\x12\x18\n" +
+	"\acontent\x18\a \x01(\tR\acontent\x12!\n" +
+	"\x06anchor\x18\b \x01(\v2\t.msg.RectR\x06anchor\x12\x1a\n" +
+	"\bresolved\x18\t \x01(\bR\bresolved\x12\x1f\n" +
+	"\vresolved_by\x18\n" +
+	" \x01(\tR\n" +
+	"resolvedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\v \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\x03R\tupdatedAt\"e\n" +
+	"\x0eCommentDeleted\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x02 \x01(\tR\tcommentId\x12\x1b\n" +
+	"\tparent_id\x18\x03 \x01(\tR\bparentId\"\x86\x01\n" +
+	"\x0fVersionRestored\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x02 \x01(\tR\tversionId\x12\x1f\n" +
+	"\vrestored_by\x18\x03 \x01(\tR\n" +
+	"restoredBy\x12\x1a\n" +
+	"\brevision\x18\x04 \x01(\x04R\brevision*'\n" +
+	"\tShapeType\x12\r\n" +
+	"\tRECTANGLE\x10\x00\x12\v\n" +
+	"\aELLIPSE\x10\x01*$\n" +
+	"\n" +
+	"BrushShape\x12\n" +
+	"\n" +
+	"\x06SQUARE\x10\x00\x12\n" +
+	"\n" +
+	"\x06CIRCLE\x10\x01*V\n" +
+	"\rPaletteAction\x12\r\n" +
+	"\tADD_COLOR\x10\x00\x12\x10\n" +
+	"\fUPDATE_COLOR\x10\x01\x12\x10\n" +
+	"\fREMOVE_COLOR\x10\x02\x12\x12\n" +
+	"\x0eREPLACE_COLORS\x10\x03B\x11Z\x0f./websocket;msgb\x06proto3"
 
 var (
 	file_websocket_msg_messages_proto_rawDescOnce sync.Once
@@ -424,22 +3695,101 @@ func file_websocket_msg_messages_proto_rawDescGZIP() []byte {
 	return file_websocket_msg_messages_proto_rawDescData
 }
 
-var file_websocket_msg_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_websocket_msg_messages_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_websocket_msg_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 48)
 var file_websocket_msg_messages_proto_goTypes = []any{
-	(*WSMessage)(nil),           // 0: msg.WSMessage
-	(*Auth)(nil),                // 1: msg.Auth
-	(*WSError)(nil),             // 2: msg.WSError
-	(*MousePosition)(nil),       // 3: msg.MousePosition
-	(*MousePositionUpdate)(nil), // 4: msg.MousePositionUpdate
-	(*JoinRoom)(nil),            // 5: msg.JoinRoom
-	(*JoinRoomSuccess)(nil),     // 6: msg.JoinRoomSuccess
+	(ShapeType)(0),              // 0: msg.ShapeType
+	(BrushShape)(0),             // 1: msg.BrushShape
+	(PaletteAction)(0),          // 2: msg.PaletteAction
+	(*WSMessage)(nil),           // 3: msg.WSMessage
+	(*Auth)(nil),                // 4: msg.Auth
+	(*WSError)(nil),             // 5: msg.WSError
+	(*MousePosition)(nil),       // 6: msg.MousePosition
+	(*MousePositionUpdate)(nil), // 7: msg.MousePositionUpdate
+	(*CursorBatch)(nil),         // 8: msg.CursorBatch
+	(*JoinRoom)(nil),            // 9: msg.JoinRoom
+	(*JoinRoomSuccess)(nil),     // 10: msg.JoinRoomSuccess
+	(*PixelUpdate)(nil),         // 11: msg.PixelUpdate
+	(*SetPixels)(nil),           // 12: msg.SetPixels
+	(*PixelsUpdate)(nil),        // 13: msg.PixelsUpdate
+	(*CanvasSnapshot)(nil),      // 14: msg.CanvasSnapshot
+	(*LeaveRoom)(nil),           // 15: msg.LeaveRoom
+	(*Participant)(nil),         // 16: msg.Participant
+	(*RoomParticipants)(nil),    // 17: msg.RoomParticipants
+	(*UserJoined)(nil),          // 18: msg.UserJoined
+	(*UserLeft)(nil),            // 19: msg.UserLeft
+	(*AccessUpdated)(nil),       // 20: msg.AccessUpdated
+	(*AccessRevoked)(nil),       // 21: msg.AccessRevoked
+	(*Latency)(nil),             // 22: msg.Latency
+	(*OperationAck)(nil),        // 23: msg.OperationAck
+	(*CaughtUp)(nil),            // 24: msg.CaughtUp
+	(*Undo)(nil),                // 25: msg.Undo
+	(*Redo)(nil),                // 26: msg.Redo
+	(*Layer)(nil),               // 27: msg.Layer
+	(*LayersUpdate)(nil),        // 28: msg.LayersUpdate
+	(*Point)(nil),               // 29: msg.Point
+	(*Color)(nil),               // 30: msg.Color
+	(*FloodFill)(nil),           // 31: msg.FloodFill
+	(*DrawLine)(nil),            // 32: msg.DrawLine
+	(*DrawShape)(nil),           // 33: msg.DrawShape
+	(*BrushStamp)(nil),          // 34: msg.BrushStamp
+	(*Rect)(nil),                // 35: msg.Rect
+	(*Transform)(nil),           // 36: msg.Transform
+	(*Selection)(nil),           // 37: msg.Selection
+	(*SelectionUpdate)(nil),     // 38: msg.SelectionUpdate
+	(*SelectionEdit)(nil),       // 39: msg.SelectionEdit
+	(*ClipboardUpdate)(nil),     // 40: msg.ClipboardUpdate
+	(*Paste)(nil),               // 41: msg.Paste
+	(*MoveSelection)(nil),       // 42: msg.MoveSelection
+	(*PaletteEdit)(nil),         // 43: msg.PaletteEdit
+	(*PaletteUpdate)(nil),       // 44: msg.PaletteUpdate
+	(*ChatMessage)(nil),         // 45: msg.ChatMessage
+	(*ChatMessageReceived)(nil), // 46: msg.ChatMessageReceived
+	(*ChatSettings)(nil),        // 47: msg.ChatSettings
+	(*Comment)(nil),             // 48: msg.Comment
+	(*CommentDeleted)(nil),      // 49: msg.CommentDeleted
+	(*VersionRestored)(nil),     // 50: msg.VersionRestored
 }
 var file_websocket_msg_messages_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	30, // 0: msg.MousePosition.color:type_name -> msg.Color
+	30, // 1: msg.MousePositionUpdate.color:type_name -> msg.Color
+	7,  // 2: msg.CursorBatch.cursors:type_name -> msg.MousePositionUpdate
+	11, // 3: msg.SetPixels.pixels:type_name -> msg.PixelUpdate
+	11, // 4: msg.PixelsUpdate.pixels:type_name -> msg.PixelUpdate
+	27, // 5: msg.CanvasSnapshot.layers:type_name -> msg.Layer
+	30, // 6: msg.CanvasSnapshot.palette:type_name -> msg.Color
+	16, // 7: msg.RoomParticipants.participants:type_name -> msg.Participant
+	16, // 8: msg.UserJoined.participant:type_name -> msg.Participant
+	27, // 9: msg.LayersUpdate.layers:type_name -> msg.Layer
+	30, // 10: msg.FloodFill.color:type_name -> msg.Color
+	29, // 11: msg.DrawLine.from:type_name -> msg.Point
+	29, // 12: msg.DrawLine.to:type_name -> msg.Point
+	30, // 13: msg.DrawLine.color:type_name -> msg.Color
+	0,  // 14: msg.DrawShape.shape:type_name -> msg.ShapeType
+	29, // 15: msg.DrawShape.from:type_name -> msg.Point
+	29, // 16: msg.DrawShape.to:type_name -> msg.Point
+	30, // 17: msg.DrawShape.color:type_name -> msg.Color
+	29, // 18: msg.BrushStamp.points:type_name -> msg.Point
+	1,  // 19: msg.BrushStamp.shape:type_name -> msg.BrushShape
+	30, // 20: msg.BrushStamp.color:type_name -> msg.Color
+	35, // 21: msg.Selection.rect:type_name -> msg.Rect
+	35, // 22: msg.SelectionUpdate.rect:type_name -> msg.Rect
+	35, // 23: msg.SelectionEdit.rect:type_name -> msg.Rect
+	29, // 24: msg.Paste.position:type_name -> msg.Point
+	36, // 25: msg.Paste.transform:type_name -> msg.Transform
+	35, // 26: msg.MoveSelection.rect:type_name -> msg.Rect
+	29, // 27: msg.MoveSelection.position:type_name -> msg.Point
+	36, // 28: msg.MoveSelection.transform:type_name -> msg.Transform
+	2,  // 29: msg.PaletteEdit.action:type_name -> msg.PaletteAction
+	30, // 30: msg.PaletteEdit.color:type_name -> msg.Color
+	30, // 31: msg.PaletteEdit.colors:type_name -> msg.Color
+	30, // 32: msg.PaletteUpdate.colors:type_name -> msg.Color
+	35, // 33: msg.Comment.anchor:type_name -> msg.Rect
+	34, // [34:34] is the sub-list for method output_type
+	34, // [34:34] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_websocket_msg_messages_proto_init() }
@@ -452,13 +3802,14 @@ func file_websocket_msg_messages_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_websocket_msg_messages_proto_rawDesc), len(file_websocket_msg_messages_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      3,
+			NumMessages:   48,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_websocket_msg_messages_proto_goTypes,
 		DependencyIndexes: file_websocket_msg_messages_proto_depIdxs,
+		EnumInfos:         file_websocket_msg_messages_proto_enumTypes,
 		MessageInfos:      file_websocket_msg_messages_proto_msgTypes,
 	}.Build()
 	File_websocket_msg_messages_proto = out.File
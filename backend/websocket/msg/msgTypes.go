@@ -6,6 +6,39 @@ var (
 	ErrorMsg          WSMessageType = "error"
 	AuthMsg           WSMessageType = "auth"
 	MousePosUpdateMsg WSMessageType = "mouse_position_update"
+	CursorBatchMsg    WSMessageType = "cursor_batch"
 	JoinRoomMsg       WSMessageType = "join_room"
 	LeaveRoomMsg      WSMessageType = "leave_room"
+	SetPixelsMsg      WSMessageType = "set_pixels"
+	CanvasSnapshotMsg WSMessageType = "canvas_snapshot"
+	ParticipantsMsg   WSMessageType = "room_participants"
+	UserJoinedMsg     WSMessageType = "user_joined"
+	UserLeftMsg       WSMessageType = "user_left"
+	AccessUpdatedMsg  WSMessageType = "access_updated"
+	AccessRevokedMsg  WSMessageType = "access_revoked"
+	LatencyMsg        WSMessageType = "latency"
+	OperationAckMsg   WSMessageType = "op_ack"
+	CaughtUpMsg       WSMessageType = "caught_up"
+	UndoMsg           WSMessageType = "undo"
+	RedoMsg           WSMessageType = "redo"
+	LayersUpdateMsg   WSMessageType = "layers_updated"
+	FloodFillMsg      WSMessageType = "flood_fill"
+	DrawLineMsg       WSMessageType = "draw_line"
+	DrawShapeMsg      WSMessageType = "draw_shape"
+	BrushStampMsg     WSMessageType = "brush_stamp"
+	SelectionMsg      WSMessageType = "selection"
+	CopyMsg           WSMessageType = "copy_selection"
+	CutMsg            WSMessageType = "cut_selection"
+	ClearMsg          WSMessageType = "clear_selection"
+	ClipboardMsg      WSMessageType = "clipboard_updated"
+	PasteMsg          WSMessageType = "paste"
+	MoveMsg           WSMessageType = "move_selection"
+	PaletteEditMsg    WSMessageType = "edit_palette"
+	PaletteUpdateMsg  WSMessageType = "palette_updated"
+	ChatMsg           WSMessageType = "chat_message"
+	ChatSettingsMsg   WSMessageType = "chat_settings"
+	CommentCreateMsg  WSMessageType = "comment_created"
+	CommentUpdateMsg  WSMessageType = "comment_updated"
+	CommentDeleteMsg  WSMessageType = "comment_deleted"
+	VersionRestoreMsg WSMessageType = "version_restored"
 )
@@ -0,0 +1,102 @@
+package websocket
+
+import (
+	"github.com/CDavidSV/Pixio/config"
+	"github.com/CDavidSV/Pixio/websocket/msg"
+	"google.golang.org/protobuf/proto"
+)
+
+// roomOp is an accepted change to the room, kept so that reconnecting clients can catch up without a full snapshot.
+type roomOp struct {
+	seq     uint64
+	message []byte // Encoded message that was broadcast for the change
+}
+
+// commit assigns the next sequence number to an accepted change, records it in the operation log
+// and sends it to every member of the room except the sender, who receives an acknowledgement instead.
+// A nil sender sends the change to every member. Must be called while holding r.mu.
+func (r *Room) commit(sender *WSClient, msgType msg.WSMessageType, build func(seq uint64) proto.Message) (uint64, error) {
+	seq := r.revision + 1
+	message, err := encodeMessage(msgType, build(seq))
+	if err != nil {
+		return 0, err
+	}
+
+	r.revision = seq
+	r.dirty = true
+	r.countEdit()
+
+	r.opLog = append(r.opLog, roomOp{seq: seq, message: message})
+	if overflow := len(r.opLog) - config.RoomOpLogSize; overflow > 0 {
+		r.opLog = append(r.opLog[:0], r.opLog[overflow:]...)
+	}
+
+	// Queueing never blocks, so the messages are sent while holding the lock to keep them in sequence order
+	for _, c := range r.Clients {
+		if c.WSClient == sender {
+			continue
+		}
+
+		c.WSClient.enqueue(message, false)
+	}
+
+	if sender != nil {
+		sendMessage(sender, msg.OperationAckMsg, &msg.OperationAck{
+			RoomId: r.CanvasID,
+			Seq:    seq,
+		})
+	}
+
+	return seq, nil
+}
+
+// missedOps returns the operations after the given sequence number.
+// Returns false if they are no longer in the log and the client needs a full snapshot. Must be called while holding r.mu.
+func (r *Room) missedOps(lastSeq uint64) ([]roomOp, bool) {
+	if lastSeq > r.revision {
+		return nil, false
+	}
+
+	if lastSeq == r.revision {
+		return nil, true
+	}
+
+	if len(r.opLog) == 0 || r.opLog[0].seq > lastSeq+1 {
+		return nil, false
+	}
+
+	start := int(lastSeq + 1 - r.opLog[0].seq)
+	return r.opLog[start:], true
+}
+
+// catchUp sends the operations the client missed since lastSeq.
+// Returns false if the client can't catch up and needs a full snapshot, which is also the case when the missed operations
+// don't fit in the client's send queue since queueing them would disconnect it as a slow client. Must be called while holding r.mu.
+func (r *Room) catchUp(client *WSClient, epoch string, lastSeq uint64) bool {
+	// Sequence numbers are only meaningful within the same room instance
+	if lastSeq == 0 || epoch != r.epoch {
+		return false
+	}
+
+	ops, ok := r.missedOps(lastSeq)
+	if !ok {
+		return false
+	}
+
+	// One more message is queued to tell the client it caught up
+	if len(ops)+1 > client.queueSpace() {
+		return false
+	}
+
+	for _, op := range ops {
+		client.enqueue(op.message, false)
+	}
+
+	sendMessage(client, msg.CaughtUpMsg, &msg.CaughtUp{
+		RoomId:   r.CanvasID,
+		FromSeq:  lastSeq,
+		Revision: r.revision,
+	})
+
+	return true
+}
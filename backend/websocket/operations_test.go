@@ -0,0 +1,92 @@
+package websocket
+
+import (
+	"testing"
+)
+
+func TestMissedOps(t *testing.T) {
+	// The log only keeps operations 5 to 8
+	log := []roomOp{{seq: 5}, {seq: 6}, {seq: 7}, {seq: 8}}
+
+	tests := []struct {
+		name     string
+		opLog    []roomOp
+		revision uint64
+		lastSeq  uint64
+		wantSeqs []uint64
+		wantOK   bool
+	}{
+		{name: "up to date", opLog: log, revision: 8, lastSeq: 8, wantOK: true},
+		{name: "missed the last operation", opLog: log, revision: 8, lastSeq: 7, wantSeqs: []uint64{8}, wantOK: true},
+		{name: "missed every logged operation", opLog: log, revision: 8, lastSeq: 4, wantSeqs: []uint64{5, 6, 7, 8}, wantOK: true},
+		{name: "missed operations no longer logged", opLog: log, revision: 8, lastSeq: 3, wantOK: false},
+		{name: "ahead of the room", opLog: log, revision: 8, lastSeq: 9, wantOK: false},
+		{name: "empty log", revision: 8, lastSeq: 7, wantOK: false},
+		{name: "empty log and up to date", revision: 8, lastSeq: 8, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Room{opLog: tt.opLog, revision: tt.revision}
+
+			ops, ok := r.missedOps(tt.lastSeq)
+			if ok != tt.wantOK {
+				t.Fatalf("missedOps(%d) ok = %v, want %v", tt.lastSeq, ok, tt.wantOK)
+			}
+
+			if len(ops) != len(tt.wantSeqs) {
+				t.Fatalf("missedOps(%d) returned %d operations, want %d", tt.lastSeq, len(ops), len(tt.wantSeqs))
+			}
+
+			for i, op := range ops {
+				if op.seq != tt.wantSeqs[i] {
+					t.Errorf("operation %d has seq %d, want %d", i, op.seq, tt.wantSeqs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCatchUpQueueSpace(t *testing.T) {
+	tests := []struct {
+		name      string
+		queueSize int
+		lastSeq   uint64
+		want      bool
+	}{
+		{name: "operations fit in the queue", queueSize: 4, lastSeq: 6, want: true},
+		{name: "operations and caught up message fill the queue", queueSize: 3, lastSeq: 6, want: true},
+		{name: "operations don't fit in the queue", queueSize: 2, lastSeq: 6, want: false},
+		{name: "reconnected without a sequence number", queueSize: 4, lastSeq: 0, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Room{
+				opLog:    []roomOp{{seq: 7, message: []byte{7}}, {seq: 8, message: []byte{8}}},
+				revision: 8,
+				epoch:    "epoch",
+			}
+			client := &WSClient{send: make(chan []byte, tt.queueSize), done: make(chan struct{}), stats: &SendStats{}}
+
+			if got := r.catchUp(client, "epoch", tt.lastSeq); got != tt.want {
+				t.Fatalf("catchUp() = %v, want %v", got, tt.want)
+			}
+
+			// The missed operations are followed by the caught up message
+			wantQueued := 0
+			if tt.want {
+				wantQueued = 3
+			}
+			if len(client.send) != wantQueued {
+				t.Errorf("catchUp() queued %d messages, want %d", len(client.send), wantQueued)
+			}
+
+			select {
+			case <-client.done:
+				t.Error("catchUp() closed the client as a slow consumer")
+			default:
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package websocket
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/CDavidSV/Pixio/services"
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// recordChanges attributes the changed pixels to the user and appends their new flattened value to the operation log.
+// Must be called while holding r.mu, after the changes were applied to the layers.
+func (r *Room) recordChanges(userID string, changes []pixelChange) {
+	r.attribute(userID, changes)
+	if len(changes) == 0 {
+		return
+	}
+
+	// The same pixel may be changed more than once in a batch, only its final value is logged
+	changed := make(map[int]struct{}, len(changes))
+	for _, c := range changes {
+		changed[c.index] = struct{}{}
+	}
+
+	indexes := slices.Sorted(maps.Keys(changed))
+	pixels := make([]types.Pixel, len(indexes))
+	for i, index := range indexes {
+		pixels[i] = services.FlattenPixel(r.Layers, index)
+	}
+
+	r.pendingOps = append(r.pendingOps, services.CanvasChange{
+		Indexes: indexes,
+		Pixels:  pixels,
+		Time:    time.Now(),
+	})
+}
+
+// logKeyframe appends the whole flattened canvas to the operation log. Must be called while holding r.mu.
+// flattened may be nil, in which case the room's layers are flattened.
+func (r *Room) logKeyframe(flattened []types.Pixel) {
+	if flattened == nil {
+		flattened = r.flatten()
+	}
+
+	r.pendingOps = append(r.pendingOps, services.CanvasChange{
+		Keyframe: true,
+		Pixels:   flattened,
+		Time:     time.Now(),
+	})
+	r.dirty = true
+}
+
+// Flush saves the pending changes of the live canvas room, if any, so that they are visible to queries on the stored canvas.
+func (h *Hub) Flush(canvasID string) error {
+	room := h.getRoom(canvasID)
+	if room == nil {
+		return nil
+	}
+
+	return room.flush()
+}
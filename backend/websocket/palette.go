@@ -0,0 +1,178 @@
+package websocket
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/CDavidSV/Pixio/services"
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/websocket/msg"
+	"google.golang.org/protobuf/proto"
+)
+
+// inPalette reports whether the pixel can be painted in the room. Must be called while holding r.mu.
+// Fully transparent pixels are always allowed so that locked palettes can still erase.
+func (r *Room) inPalette(pixel types.Pixel) bool {
+	return !r.PaletteLocked || pixel.A == 0 || r.Palette.Contains(pixel)
+}
+
+// paletteColor returns the pixel if it can be painted, otherwise the closest color of the locked palette.
+// Used for pixels that don't come from a color the client picked, like pasted or imported ones. Must be called while holding r.mu.
+func (r *Room) paletteColor(pixel types.Pixel) types.Pixel {
+	if r.inPalette(pixel) {
+		return pixel
+	}
+
+	return r.Palette.Nearest(pixel)
+}
+
+// commitPalette broadcasts the room's palette to every client in the room. Must be called while holding r.mu.
+func (r *Room) commitPalette() {
+	_, err := r.commit(nil, msg.PaletteUpdateMsg, func(seq uint64) proto.Message {
+		return &msg.PaletteUpdate{
+			RoomId:   r.CanvasID,
+			Colors:   paletteMessage(r.Palette),
+			Locked:   r.PaletteLocked,
+			Revision: seq,
+		}
+	})
+	if err != nil {
+		slog.Error("Failed to broadcast palette update", "canvasID", r.CanvasID, "error", err)
+	}
+}
+
+// EditPalette applies the edit to the canvas palette and broadcasts the result if the canvas room is live.
+// Edits to a live canvas are serialized by the room so that every client sees them in the same order.
+func (h *Hub) EditPalette(canvasID string, edit services.PaletteEdit) (types.Palette, error) {
+	room := h.getRoom(canvasID)
+	if room == nil {
+		return h.services.CanvasService.EditPalette(canvasID, edit)
+	}
+
+	// The edit is saved before taking the room lock so that drawing doesn't wait for the database,
+	// edits are still applied to the room in the order they were saved
+	room.paletteMu.Lock()
+	defer room.paletteMu.Unlock()
+
+	palette, err := h.services.CanvasService.EditPalette(canvasID, edit)
+	if err != nil {
+		return nil, err
+	}
+
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	room.Palette = palette
+	room.commitPalette()
+	return palette, nil
+}
+
+// SetPaletteLocked applies the palette lock setting to the canvas room.
+func (h *Hub) SetPaletteLocked(canvasID string, locked bool) {
+	room := h.getRoom(canvasID)
+	if room == nil {
+		return
+	}
+
+	room.mu.Lock()
+	defer room.mu.Unlock()
+
+	if room.PaletteLocked == locked {
+		return
+	}
+
+	room.PaletteLocked = locked
+	room.commitPalette()
+}
+
+func (h *Hub) editPalette(client *WSClient, payload []byte) {
+	paletteEdit := &msg.PaletteEdit{}
+	if err := proto.Unmarshal(payload, paletteEdit); err != nil {
+		sendError(client, msg.PaletteEditMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	room := client.GetRoom(paletteEdit.RoomId)
+	if room == nil {
+		sendError(client, msg.PaletteEditMsg, ErrRoomNotFound.Error())
+		return
+	}
+
+	if _, ok := authorize(client, room, msg.PaletteEditMsg, types.Editor); !ok {
+		return
+	}
+
+	var edit services.PaletteEdit
+	switch paletteEdit.Action {
+	case msg.PaletteAction_ADD_COLOR, msg.PaletteAction_UPDATE_COLOR:
+		color, ok := colorFromMessage(paletteEdit.Color)
+		if !ok {
+			sendError(client, msg.PaletteEditMsg, ErrInvalidColor.Error())
+			return
+		}
+
+		if paletteEdit.Action == msg.PaletteAction_ADD_COLOR {
+			edit = services.AddColor(color)
+		} else {
+			edit = services.UpdateColor(int(paletteEdit.Index), color)
+		}
+	case msg.PaletteAction_REMOVE_COLOR:
+		edit = services.RemoveColor(int(paletteEdit.Index))
+	case msg.PaletteAction_REPLACE_COLORS:
+		colors := make(types.Palette, len(paletteEdit.Colors))
+		for i, c := range paletteEdit.Colors {
+			color, ok := colorFromMessage(c)
+			if !ok {
+				sendError(client, msg.PaletteEditMsg, ErrInvalidColor.Error())
+				return
+			}
+
+			colors[i] = color
+		}
+		edit = services.ReplaceColors(colors)
+	default:
+		sendError(client, msg.PaletteEditMsg, ErrInvalidColor.Error())
+		return
+	}
+
+	if _, err := h.EditPalette(room.CanvasID, edit); err != nil {
+		switch {
+		case errors.Is(err, types.ErrPaletteFull):
+			sendError(client, msg.PaletteEditMsg, ErrPaletteFull.Error())
+		case errors.Is(err, types.ErrInvalidColorIndex):
+			sendError(client, msg.PaletteEditMsg, ErrInvalidColorIndex.Error())
+		default:
+			slog.Error("Failed to edit palette", "canvasID", room.CanvasID, "error", err)
+			sendError(client, msg.PaletteEditMsg, ErrUnexpected.Error())
+		}
+	}
+}
+
+// colorFromMessage converts a color message into a pixel, reporting whether the channels are valid.
+func colorFromMessage(color *msg.Color) (types.Pixel, bool) {
+	if color == nil || color.R > 255 || color.G > 255 || color.B > 255 || color.A > 255 {
+		return types.Pixel{}, false
+	}
+
+	return types.Pixel{
+		R: uint8(color.R),
+		G: uint8(color.G),
+		B: uint8(color.B),
+		A: uint8(color.A),
+	}, true
+}
+
+// paletteMessage converts the palette into color messages.
+func paletteMessage(palette types.Palette) []*msg.Color {
+	colors := make([]*msg.Color, len(palette))
+	for i, color := range palette {
+		colors[i] = &msg.Color{
+			R: uint32(color.R),
+			G: uint32(color.G),
+			B: uint32(color.B),
+			A: uint32(color.A),
+		}
+	}
+
+	return colors
+}
@@ -0,0 +1,77 @@
+package websocket
+
+import (
+	"slices"
+
+	"github.com/CDavidSV/Pixio/websocket/msg"
+)
+
+// Colors assigned to the cursors of the users in a room
+var cursorColors = []string{
+	"#e6194b",
+	"#3cb44b",
+	"#4363d8",
+	"#f58231",
+	"#911eb4",
+	"#42d4f4",
+	"#f032e6",
+	"#bfef45",
+	"#fabed4",
+	"#469990",
+	"#dcbeff",
+	"#9a6324",
+	"#800000",
+	"#aaffc3",
+	"#808000",
+	"#000075",
+}
+
+// nextCursorColor returns the first color not used by anyone in the room. Must be called while holding r.mu.
+func (r *Room) nextCursorColor() string {
+	used := make([]string, 0, len(r.Clients))
+	users := make(map[string]bool, len(r.Clients))
+	for _, c := range r.Clients {
+		used = append(used, c.CursorColor)
+		users[c.WSClient.ID] = true
+	}
+
+	for _, color := range cursorColors {
+		if !slices.Contains(used, color) {
+			return color
+		}
+	}
+
+	// Every color is taken, start reusing them
+	return cursorColors[len(users)%len(cursorColors)]
+}
+
+// Participants returns the list of users currently in the room, with one entry per user regardless of their number of connections.
+func (r *Room) Participants() []*msg.Participant {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	participants := make([]*msg.Participant, 0, len(r.Clients))
+	byUser := make(map[string]*msg.Participant, len(r.Clients))
+	for _, c := range r.Clients {
+		if p, ok := byUser[c.WSClient.ID]; ok {
+			p.Connections++
+			continue
+		}
+
+		p := c.participant()
+		byUser[c.WSClient.ID] = p
+		participants = append(participants, p)
+	}
+
+	return participants
+}
+
+func (c *ClientWithPerms) participant() *msg.Participant {
+	return &msg.Participant{
+		UserId:      c.User.ID,
+		Username:    c.User.Username,
+		AvatarUrl:   string(c.User.AvatarURL),
+		CursorColor: c.CursorColor,
+		Connections: 1,
+	}
+}
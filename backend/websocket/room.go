@@ -3,12 +3,17 @@ package websocket
 import (
 	"errors"
 	"log/slog"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/CDavidSV/Pixio/config"
+	"github.com/CDavidSV/Pixio/services"
 	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/utils"
 	"github.com/CDavidSV/Pixio/websocket/msg"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -21,23 +26,51 @@ const (
 )
 
 type Room struct {
-	CanvasID    string
-	Clients     map[string]*ClientWithPerms
-	Width       uint16
-	Height      uint16
-	PixelData   []types.Pixel
-	mu          sync.RWMutex
-	hub         *Hub
-	deleteTimer *time.Timer
-	loadStatus  LoadStatus
+	CanvasID      string
+	Clients       map[string]*ClientWithPerms // connID -> client
+	Width         uint16
+	Height        uint16
+	Layers        []*types.LoadedLayer // Ordered from bottom to top
+	Palette       types.Palette
+	PaletteLocked bool // Whether pixels can only be painted with palette colors
+	ViewerChat    bool // Whether viewers can send chat messages
+	mu            sync.RWMutex
+	hub           *Hub
+	deleteTimer   *time.Timer
+	evictRetries  int // Number of times saving the room failed since it became empty
+	loadStatus    LoadStatus
+	paletteMu     sync.Mutex                  // Serializes palette edits, held while they are saved
+	dirty         bool                        // Whether the room has changes that have not been saved yet
+	dirtyTiles    map[string]map[int]struct{} // layerID -> tiles of the layer with unsaved changes
+	dirtyCanvas   map[int]struct{}            // Tiles of the flattened canvas that must be recomposed
+	flushMu       sync.Mutex                  // Serializes flushes so that an older snapshot never overwrites a newer one
+	done          chan struct{}               // Closed when the room is evicted to stop the flush loop
+	revision      uint64                      // Sequence number of the last accepted change to the room
+	pending       []*WSClient                 // Clients waiting for a snapshot while the canvas is loading
+	epoch         string                      // Identifies this instance of the room, sequence numbers restart when the room is recreated
+	opLog         []roomOp                    // Most recent operations, oldest first
+	history       map[string]*userHistory
+	chatLimits    map[string]*chatLimit // userID -> chat messages sent in the current rate limit window
+	cursorMu      sync.Mutex
+	cursors       map[string]*msg.MousePositionUpdate // connID -> latest cursor position that was not broadcast yet
+	editCount     int                                 // Number of edits since the last version was saved
+	lastVersionAt time.Time                           // When the last version was saved, or when the room was created
+	versioning    bool                                // Whether an automatic version is being saved
+	pendingEdits  map[string]services.PixelEdits      // userID -> pixels changed by the user that were not saved yet
+	pendingOps    []services.CanvasChange             // Operations that were not added to the saved operation log yet, oldest first
 }
 
 type ClientWithPerms struct {
-	WSClient *WSClient
-	Perms    *types.UserAccess
+	WSClient    *WSClient
+	Perms       *types.UserAccess
+	User        types.User
+	CursorColor string
 }
 
-func (r *Room) SetClient(client *WSClient, accessRules *types.UserAccess) {
+// SetClient adds the connection to the room and sends it the operations it missed since lastSeq, if it was previously connected to the same room instance.
+// Both happen under the same lock so that no operation is missed or received twice.
+// Returns the new member, whether it is the first connection of the user in the room and whether the client still needs a full snapshot.
+func (r *Room) SetClient(client *WSClient, accessRules *types.UserAccess, user types.User, epoch string, lastSeq uint64) (*ClientWithPerms, bool, bool) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -45,73 +78,555 @@ func (r *Room) SetClient(client *WSClient, accessRules *types.UserAccess) {
 		r.deleteTimer.Stop()
 		r.deleteTimer = nil
 	}
+	r.evictRetries = 0
+
+	// Every connection of a user shares the same cursor color
+	existing := r.userMember(client.ID, client.connID)
+	cursorColor := ""
+	if existing != nil {
+		cursorColor = existing.CursorColor
+	} else {
+		cursorColor = r.nextCursorColor()
+	}
+
+	member := &ClientWithPerms{
+		WSClient:    client,
+		Perms:       accessRules,
+		User:        user,
+		CursorColor: cursorColor,
+	}
+	r.Clients[client.connID] = member
+
+	caughtUp := r.loadStatus == Loaded && r.catchUp(client, epoch, lastSeq)
+	return member, existing == nil, !caughtUp
+}
+
+// userMember returns any connection of the user in the room other than the excluded one. Must be called while holding r.mu.
+func (r *Room) userMember(userID, excludedConnID string) *ClientWithPerms {
+	for connID, c := range r.Clients {
+		if c.WSClient.ID == userID && connID != excludedConnID {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// CheckRole returns the client's membership in the room if the client has at least the given role.
+func (r *Room) CheckRole(client *WSClient, role types.AccessRole) (*ClientWithPerms, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	member, ok := r.Clients[client.connID]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
 
-	r.Clients[client.ID] = &ClientWithPerms{
-		WSClient: client,
-		Perms:    accessRules,
+	if !member.HasRole(role) {
+		return nil, ErrMissingPermissions
 	}
+
+	return member, nil
+}
+
+// HasRole reports whether the member's access role grants at least the privileges of the given role.
+// Must be called while holding the room's lock.
+func (c *ClientWithPerms) HasRole(role types.AccessRole) bool {
+	// Lower roles have more privileges
+	return c.Perms != nil && c.Perms.AccessRole <= role
 }
 
-func (r *Room) RemoveClient(clientID string) {
+// RemoveClient removes the connection from the room.
+// Returns whether the connection was a member and whether it was the last connection of the user in the room.
+func (r *Room) RemoveClient(client *WSClient) (bool, bool) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	delete(r.Clients, clientID)
+
+	if _, ok := r.Clients[client.connID]; !ok {
+		return false, false
+	}
+	delete(r.Clients, client.connID)
+	lastConnection := r.userMember(client.ID, "") == nil
 
 	if len(r.Clients) == 0 {
+		// Save the changes right away, the room might not be used again for a while
+		go r.persist()
 		r.deleteEmtyRoomTimer(r.CanvasID)
 	}
+
+	return true, lastConnection
 }
 
+// deleteEmtyRoomTimer schedules the eviction of the room. Must be called while holding r.mu.
 func (r *Room) deleteEmtyRoomTimer(roomID string) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	if r.deleteTimer != nil {
 		r.deleteTimer.Stop()
 		r.deleteTimer = nil
 	}
 
-	r.deleteTimer = time.AfterFunc(time.Minute*3, func() {
+	r.deleteTimer = time.AfterFunc(config.RoomDeleteDelay, func() {
+		// Keep the edits made since the last version, the next room instance starts counting again
+		r.checkEvictionVersion()
+
+		// Flush before evicting so that the next time the room is created it loads the latest data
+		if err := r.flush(); err != nil {
+			r.mu.Lock()
+			r.evictRetries++
+
+			// Changes to a canvas that was deleted can never be saved, other errors are retried a few times
+			if !isMissingCanvasError(err) && r.evictRetries < config.RoomEvictRetries {
+				if len(r.Clients) == 0 {
+					r.deleteEmtyRoomTimer(roomID)
+				}
+				r.mu.Unlock()
+
+				slog.Error("Failed to save canvas before evicting room, retrying later", "canvasID", roomID, "Error", err.Error())
+				return
+			}
+
+			if len(r.Clients) != 0 {
+				r.mu.Unlock()
+				return
+			}
+
+			r.discardChanges()
+			r.mu.Unlock()
+
+			slog.Error("Failed to save canvas before evicting room, evicting it with unsaved changes", "canvasID", roomID, "Error", err.Error())
+		}
+
 		r.hub.roomMutex.Lock()
 		defer r.hub.roomMutex.Unlock()
 
 		room, exists := r.hub.rooms[roomID]
-		if !exists {
+		if !exists || room != r {
 			return
 		}
 
 		room.mu.RLock()
 		defer room.mu.RUnlock()
 
-		if len(room.Clients) == 0 {
-			delete(r.hub.rooms, roomID)
+		// Someone joined or new changes were made after the flush, don't evict
+		if len(room.Clients) != 0 || room.dirty {
+			return
 		}
+
+		delete(r.hub.rooms, roomID)
+		close(room.done)
 	})
 }
 
-func (r *Room) loadCanvasData(data []byte) {
-	r.loadStatus = Loading
-	defer func() { r.loadStatus = Loaded }()
+// flush writes the room's layers to the database if there are unsaved changes.
+func (r *Room) flush() error {
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+
+	r.mu.Lock()
+	if !r.dirty || r.loadStatus != Loaded {
+		r.mu.Unlock()
+		return nil
+	}
+
+	layerTiles, canvasTiles := r.takeDirtyTiles()
+	edits := r.pendingEdits
+	r.pendingEdits = nil
+	changes := r.pendingOps
+	r.pendingOps = nil
+	r.dirty = false
+	r.mu.Unlock()
+
+	if len(layerTiles) > 0 || len(canvasTiles) > 0 {
+		if err := r.hub.services.CanvasService.SaveTiles(r.CanvasID, layerTiles, canvasTiles); err != nil {
+			// Mark the tiles as dirty again so the changes are retried on the next flush
+			r.mu.Lock()
+			r.restoreDirtyTiles(layerTiles, canvasTiles)
+			r.restorePendingEdits(edits)
+			r.pendingOps = append(changes, r.pendingOps...)
+			r.dirty = true
+			r.mu.Unlock()
+			return err
+		}
+	}
+
+	if err := r.hub.services.CanvasService.SaveEdits(r.CanvasID, edits); err != nil {
+		r.mu.Lock()
+		r.restorePendingEdits(edits)
+		r.pendingOps = append(changes, r.pendingOps...)
+		r.dirty = true
+		r.mu.Unlock()
+		return err
+	}
+
+	if err := r.hub.services.CanvasService.SaveChanges(r.CanvasID, changes); err != nil {
+		r.mu.Lock()
+		r.pendingOps = append(changes, r.pendingOps...)
+		r.dirty = true
+		r.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// discardChanges drops the changes that were not saved yet, used when they can no longer be saved. Must be called while holding r.mu.
+func (r *Room) discardChanges() {
+	r.dirty = false
+	r.dirtyTiles = nil
+	r.dirtyCanvas = nil
+	r.pendingEdits = nil
+	r.pendingOps = nil
+	r.editCount = 0
+}
+
+// isMissingCanvasError reports whether saving failed because the canvas or one of its layers no longer exists.
+func isMissingCanvasError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return true
+	}
+
+	return errors.Is(err, pgx.ErrNoRows) || errors.Is(err, types.ErrCanvasDoesNotExist)
+}
+
+func (r *Room) persist() {
+	if err := r.flush(); err != nil {
+		slog.Error("Failed to save canvas pixel data", "canvasID", r.CanvasID, "Error", err.Error())
+	}
+}
+
+// flushLoop periodically saves the room's pixel data and broadcasts the pending cursor positions until the room is evicted.
+func (r *Room) flushLoop() {
+	ticker := time.NewTicker(config.RoomFlushInterval)
+	defer ticker.Stop()
+	cursorTicker := time.NewTicker(config.RoomCursorInterval)
+	defer cursorTicker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.persist()
+			r.checkVersionInterval()
+			r.pruneChatLimits()
+		case <-cursorTicker.C:
+			r.flushCursors()
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// cloneLayers returns a copy of the room's layers that can be used without holding the lock. Must be called while holding r.mu.
+func (r *Room) cloneLayers() []types.LoadedLayer {
+	layers := make([]types.LoadedLayer, len(r.Layers))
+	for i, layer := range r.Layers {
+		layers[i] = *layer
+		layers[i].PixelData = slices.Clone(layer.PixelData)
+	}
+
+	return layers
+}
+
+// getLayer returns the layer with the given ID, or nil if the room has no such layer. Must be called while holding r.mu.
+func (r *Room) getLayer(layerID string) *types.LoadedLayer {
+	for _, layer := range r.Layers {
+		if layer.ID == layerID {
+			return layer
+		}
+	}
+
+	return nil
+}
+
+// loadCanvasData loads the canvas layers and sends the resulting snapshot to every client that was waiting for it.
+func (r *Room) loadCanvasData() {
+	// The lock is held until the room is loaded, otherwise a restore could replace the stored layers after they were read
+	unlock := r.hub.lockCanvas(r.CanvasID)
+	loaded, err := r.hub.services.CanvasService.LoadLayers(r.CanvasID, r.Width, r.Height)
+
+	var hasOps bool
+	var opsErr error
+	if err == nil {
+		hasOps, opsErr = r.hub.services.CanvasService.HasOps(r.CanvasID)
+	}
+
+	r.mu.Lock()
+	pending := r.pendingClients()
+	r.pending = nil
+
+	if err != nil {
+		// Go back to not loaded so that the next client that joins retries the load
+		r.loadStatus = NotLoaded
+		r.mu.Unlock()
+		unlock()
+
+		slog.Error("Failed to load canvas pixel data", "canvasID", r.CanvasID, "Error", err.Error())
+		for _, c := range pending {
+			sendError(c, msg.JoinRoomMsg, ErrLoadingCanvas.Error())
+		}
+		return
+	}
+
+	r.Layers = make([]*types.LoadedLayer, len(loaded))
+	for i := range loaded {
+		r.Layers[i] = &loaded[i]
+	}
+	r.loadStatus = Loaded
+
+	// Canvases with pixels from before the operation log existed start their log at the loaded canvas
+	if opsErr != nil {
+		slog.Error("Failed to check canvas operation log", "canvasID", r.CanvasID, "Error", opsErr.Error())
+	} else if !hasOps {
+		r.logKeyframe(nil)
+	}
+
+	layers := r.cloneLayers()
+	palette := slices.Clone(r.Palette)
+	paletteLocked := r.PaletteLocked
+	revision := r.revision
+	r.mu.Unlock()
+	unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	snapshot, err := r.newSnapshot(layers, palette, paletteLocked, revision)
+	if err != nil {
+		slog.Error("Failed to create canvas snapshot", "canvasID", r.CanvasID, "Error", err.Error())
+		for _, c := range pending {
+			sendError(c, msg.CanvasSnapshotMsg, ErrMarshallingMsg.Error())
+		}
+		return
+	}
+
+	for _, c := range pending {
+		sendMessage(c, msg.CanvasSnapshotMsg, snapshot)
+	}
+}
+
+// pendingClients returns the queued clients that are still in the room. Must be called while holding r.mu.
+func (r *Room) pendingClients() []*WSClient {
+	clients := make([]*WSClient, 0, len(r.pending))
+	for _, c := range r.pending {
+		if _, ok := r.Clients[c.connID]; ok {
+			clients = append(clients, c)
+		}
+	}
+
+	return clients
+}
+
+// SendSnapshot sends the current pixel state of the room to the client.
+// If the canvas is still loading the client is queued and served once the load finishes.
+func (r *Room) SendSnapshot(client *WSClient) {
+	r.mu.Lock()
+	switch r.loadStatus {
+	case NotLoaded:
+		r.loadStatus = Loading
+		r.pending = append(r.pending, client)
+		r.mu.Unlock()
+
+		go r.loadCanvasData()
+		return
+	case Loading:
+		r.pending = append(r.pending, client)
+		r.mu.Unlock()
+		return
+	}
+
+	layers := r.cloneLayers()
+	palette := slices.Clone(r.Palette)
+	paletteLocked := r.PaletteLocked
+	revision := r.revision
+	r.mu.Unlock()
 
-	pixelData, err := r.hub.services.CanvasService.LoadCanvas(data)
+	snapshot, err := r.newSnapshot(layers, palette, paletteLocked, revision)
 	if err != nil {
-		slog.Error("Failed to load canvas pixel data", "Error", err.Error())
+		slog.Error("Failed to create canvas snapshot", "canvasID", r.CanvasID, "Error", err.Error())
+		sendError(client, msg.CanvasSnapshotMsg, ErrMarshallingMsg.Error())
 		return
 	}
 
-	r.PixelData = pixelData
+	sendMessage(client, msg.CanvasSnapshotMsg, snapshot)
+}
+
+// newSnapshot creates a snapshot with every layer, the flattened image and the palette of the canvas.
+func (r *Room) newSnapshot(layers []types.LoadedLayer, palette types.Palette, paletteLocked bool, revision uint64) (*msg.CanvasSnapshot, error) {
+	canvasService := r.hub.services.CanvasService
+
+	flattened, err := canvasService.CompressPixelData(canvasService.FlattenLayers(r.Width, r.Height, layers))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &msg.CanvasSnapshot{
+		CanvasId:  r.CanvasID,
+		Width:     uint32(r.Width),
+		Height:    uint32(r.Height),
+		PixelData: flattened,
+		Revision:  revision,
+		Layers:    make([]*msg.Layer, len(layers)),
+
+		Palette:       paletteMessage(palette),
+		PaletteLocked: paletteLocked,
+	}
+
+	for i, layer := range layers {
+		compressed, err := canvasService.CompressPixelData(layer.PixelData)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot.Layers[i] = layerMessage(&layer, i)
+		snapshot.Layers[i].PixelData = compressed
+	}
+
+	return snapshot, nil
 }
 
-func (h *Hub) LeaveRoom(roomID, clientID string) {
-	h.roomMutex.RLock()
-	defer h.roomMutex.RUnlock()
+// SetPixels writes the given pixels into the layer and broadcasts the ones that were accepted.
+// Batches sharing the same stroke ID are undone together. Pixels outside of the canvas bounds or with invalid color values are discarded.
+func (r *Room) SetPixels(sender *WSClient, layerID, strokeID string, pixels []*msg.PixelUpdate) ([]*msg.PixelUpdate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.loadStatus != Loaded {
+		return nil, ErrCanvasNotLoaded
+	}
+
+	layer := r.getLayer(layerID)
+	if layer == nil {
+		return nil, ErrLayerNotFound
+	}
+
+	if layer.Locked {
+		return nil, ErrLayerLocked
+	}
+
+	accepted := make([]*msg.PixelUpdate, 0, len(pixels))
+	changes := make([]pixelChange, 0, len(pixels))
+	outsidePalette := false
+	for _, p := range pixels {
+		if p.X >= uint32(r.Width) || p.Y >= uint32(r.Height) {
+			continue
+		}
+
+		if p.R > 255 || p.G > 255 || p.B > 255 || p.A > 255 {
+			continue
+		}
+
+		index := int(p.Y)*int(r.Width) + int(p.X)
+		if index >= len(layer.PixelData) {
+			continue
+		}
+
+		pixel := types.Pixel{
+			R: uint8(p.R),
+			G: uint8(p.G),
+			B: uint8(p.B),
+			A: uint8(p.A),
+		}
+
+		if !r.inPalette(pixel) {
+			outsidePalette = true
+			continue
+		}
+
+		changes = append(changes, pixelChange{index: index, before: layer.PixelData[index], after: pixel})
+		layer.PixelData[index] = pixel
+		r.markDirty(layer.ID, index)
+		accepted = append(accepted, p)
+	}
+	r.recordChanges(sender.ID, changes)
+
+	if len(accepted) == 0 {
+		if outsidePalette {
+			return accepted, ErrColorNotInPalette
+		}
+		return accepted, nil
+	}
+
+	r.recordStroke(sender.ID, layer.ID, strokeID, changes)
+
+	_, err := r.commit(sender, msg.SetPixelsMsg, func(seq uint64) proto.Message {
+		return &msg.PixelsUpdate{
+			UserId:   sender.ID,
+			Pixels:   accepted,
+			Revision: seq,
+			LayerId:  layer.ID,
+		}
+	})
+
+	return accepted, err
+}
+
+// CloseRoom evicts the canvas room without saving it and kicks every connection in it, used when the canvas is deleted.
+func (h *Hub) CloseRoom(canvasID string) {
+	h.roomMutex.Lock()
+	room, exists := h.rooms[canvasID]
+	if exists {
+		delete(h.rooms, canvasID)
+		close(room.done)
+	}
+	h.roomMutex.Unlock()
 
-	room, exists := h.rooms[roomID]
 	if !exists {
 		return
 	}
 
-	room.RemoveClient(clientID)
+	room.mu.Lock()
+	room.discardChanges()
+	clients := make([]*WSClient, 0, len(room.Clients))
+	for _, c := range room.Clients {
+		clients = append(clients, c.WSClient)
+	}
+	room.mu.Unlock()
+
+	for _, c := range clients {
+		sendMessage(c, msg.AccessRevokedMsg, &msg.AccessRevoked{
+			RoomId: canvasID,
+			Reason: ReasonCanvasDeleted,
+		})
+		h.removeFromRoom(c, room)
+	}
+}
+
+// LeaveRoom removes every connection of the user from the room.
+func (h *Hub) LeaveRoom(roomID, userID string) {
+	h.connMutex.RLock()
+	clients := make([]*WSClient, 0, len(h.conns[userID]))
+	for _, c := range h.conns[userID] {
+		clients = append(clients, c)
+	}
+	h.connMutex.RUnlock()
+
+	for _, c := range clients {
+		if room := c.GetRoom(roomID); room != nil {
+			h.removeFromRoom(c, room)
+		}
+	}
+}
+
+// removeFromRoom removes the connection from the room on both sides.
+// The other members are notified once the user has no connections left in the room.
+func (h *Hub) removeFromRoom(client *WSClient, room *Room) {
+	client.RemoveRoom(room.CanvasID)
+	room.dropCursor(client)
+	removed, lastConnection := room.RemoveClient(client)
+	if !removed || !lastConnection {
+		return
+	}
+
+	userLeft, err := encodeMessage(msg.UserLeftMsg, &msg.UserLeft{
+		RoomId: room.CanvasID,
+		UserId: client.ID,
+	})
+	if err != nil {
+		slog.Error("Failed to encode user left message", "Error", err.Error())
+		return
+	}
+
+	broadcastMessage(client, room, userLeft)
 }
 
 func (h *Hub) joinRoom(client *WSClient, payload []byte) {
@@ -121,9 +636,14 @@ func (h *Hub) joinRoom(client *WSClient, payload []byte) {
 		return
 	}
 
-	userAccess, err := h.queries.GetUserAccess(joinRoom.CanvasId, types.CanvasObject, client.ID)
+	userAccess, err := h.services.CanvasService.GetUserCanvasAccess(joinRoom.CanvasId, client.ID)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		if errors.Is(err, types.ErrCanvasDoesNotExist) {
+			sendError(client, msg.JoinRoomMsg, ErrCanvasNotFound.Error())
+			return
+		}
+
+		if errors.Is(err, types.ErrUserAccessDenied) {
 			sendError(client, msg.JoinRoomMsg, ErrMissingPermissions.Error())
 			return
 		}
@@ -132,6 +652,12 @@ func (h *Hub) joinRoom(client *WSClient, payload []byte) {
 		return
 	}
 
+	user, err := h.queries.GetUserByID(client.ID)
+	if err != nil {
+		sendError(client, msg.JoinRoomMsg, ErrFetchingUser.Error())
+		return
+	}
+
 	canvas, err := h.queries.GetCanvas(joinRoom.CanvasId)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -147,59 +673,106 @@ func (h *Hub) joinRoom(client *WSClient, payload []byte) {
 	room, exists := h.rooms[canvas.ID]
 	if !exists {
 		room = &Room{
-			CanvasID:   canvas.ID,
-			Clients:    make(map[string]*ClientWithPerms),
-			Width:      canvas.Width,
-			Height:     canvas.Height,
-			hub:        h,
-			loadStatus: NotLoaded,
+			CanvasID:      canvas.ID,
+			Clients:       make(map[string]*ClientWithPerms),
+			Width:         canvas.Width,
+			Height:        canvas.Height,
+			Palette:       canvas.Palette,
+			PaletteLocked: canvas.PaletteLocked,
+			ViewerChat:    canvas.ViewerChat,
+			hub:           h,
+			loadStatus:    NotLoaded,
+			done:          make(chan struct{}),
+			epoch:         utils.GenerateID(),
+			history:       make(map[string]*userHistory),
+			chatLimits:    make(map[string]*chatLimit),
+			lastVersionAt: time.Now(),
 		}
 		h.rooms[canvas.ID] = room
+		go room.flushLoop()
 	}
 
-	if room.loadStatus == NotLoaded {
-		go room.loadCanvasData(canvas.PixelData)
-	}
-	h.roomMutex.Unlock()
-
-	room.SetClient(client, &userAccess)
-
 	sendMessage(client, msg.JoinRoomMsg, &msg.JoinRoomSuccess{
 		CanvasId: canvas.ID,
 		UserId:   client.ID,
 		ConnId:   client.connID,
+		Epoch:    room.epoch,
 	})
+
+	// The client is added before releasing the lock, otherwise the room could be evicted while it has no clients
+	member, firstConnection, needsSnapshot := room.SetClient(client, &userAccess, user, joinRoom.Epoch, joinRoom.LastSeq)
+	h.roomMutex.Unlock()
+	client.AddRoom(room)
+
+	sendMessage(client, msg.ParticipantsMsg, &msg.RoomParticipants{
+		RoomId:       room.CanvasID,
+		Participants: room.Participants(),
+	})
+
+	// Other connections of the same user don't count as a new participant
+	if firstConnection {
+		userJoined, err := encodeMessage(msg.UserJoinedMsg, &msg.UserJoined{
+			RoomId:      room.CanvasID,
+			Participant: member.participant(),
+		})
+		if err != nil {
+			slog.Error("Failed to encode user joined message", "Error", err.Error())
+		} else {
+			broadcastMessage(client, room, userJoined)
+		}
+	}
+
+	if needsSnapshot {
+		room.SendSnapshot(client)
+	}
 }
 
 func (h *Hub) leaveRoom(client *WSClient, payload []byte) {
+	leaveRoom := &msg.LeaveRoom{}
+	if err := proto.Unmarshal(payload, leaveRoom); err != nil {
+		sendError(client, msg.LeaveRoomMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	room := client.GetRoom(leaveRoom.RoomId)
+	if room == nil {
+		sendError(client, msg.LeaveRoomMsg, ErrRoomNotFound.Error())
+		return
+	}
 
+	h.removeFromRoom(client, room)
+	sendMessage(client, msg.LeaveRoomMsg, leaveRoom)
 }
 
-func (h *Hub) updateCursorPosition(client *WSClient, payload []byte) {
-	mousePos := &msg.MousePosition{}
-	err := proto.Unmarshal(payload, mousePos)
+func (h *Hub) setPixels(client *WSClient, payload []byte) {
+	setPixels := &msg.SetPixels{}
+	err := proto.Unmarshal(payload, setPixels)
 	if err != nil {
-		sendError(client, msg.MousePosUpdateMsg, ErrUnmarshallingMsg.Error())
+		sendError(client, msg.SetPixelsMsg, ErrUnmarshallingMsg.Error())
 		return
 	}
 
-	room := client.GetRoom(mousePos.RoomId)
+	room := client.GetRoom(setPixels.RoomId)
 	if room == nil {
-		sendError(client, msg.MousePosUpdateMsg, ErrRoomNotFound.Error())
+		sendError(client, msg.SetPixelsMsg, ErrRoomNotFound.Error())
 		return
 	}
 
-	mousePosSend := &msg.MousePositionUpdate{
-		UserId: client.ID,
-		X:      mousePos.X,
-		Y:      mousePos.Y,
+	if _, ok := authorize(client, room, msg.SetPixelsMsg, types.Editor); !ok {
+		return
 	}
 
-	message, err := encodeMessage(msg.MousePosUpdateMsg, mousePosSend)
+	accepted, err := room.SetPixels(client, setPixels.LayerId, setPixels.StrokeId, setPixels.Pixels)
 	if err != nil {
-		sendError(client, msg.MousePosUpdateMsg, ErrMarshallingMsg.Error())
+		if errors.Is(err, ErrCanvasNotLoaded) || errors.Is(err, ErrLayerNotFound) || errors.Is(err, ErrLayerLocked) || errors.Is(err, ErrColorNotInPalette) {
+			sendError(client, msg.SetPixelsMsg, err.Error())
+		} else {
+			sendError(client, msg.SetPixelsMsg, ErrMarshallingMsg.Error())
+		}
 		return
 	}
 
-	broadcastMessage(client, room, message)
+	if len(accepted) == 0 {
+		sendError(client, msg.SetPixelsMsg, ErrInvalidPixelData.Error())
+	}
 }
@@ -0,0 +1,371 @@
+package websocket
+
+import (
+	"errors"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/websocket/msg"
+	"google.golang.org/protobuf/proto"
+)
+
+// clipboard holds a rectangle of pixels copied by a user. Clipboards are shared by every connection of the user,
+// so pixels copied in one canvas can be pasted into another. They are never modified once created.
+type clipboard struct {
+	width  int
+	height int
+	pixels []types.Pixel
+}
+
+// at returns the pixel at (x, y) of the clipboard.
+func (c *clipboard) at(x, y int) types.Pixel {
+	return c.pixels[y*c.width+x]
+}
+
+// transform returns a copy of the clipboard flipped and then rotated clockwise by the given number of quarter turns.
+func (c *clipboard) transform(t *msg.Transform) *clipboard {
+	if t == nil {
+		return c
+	}
+
+	out := &clipboard{width: c.width, height: c.height, pixels: make([]types.Pixel, len(c.pixels))}
+	for y := range c.height {
+		for x := range c.width {
+			sx, sy := x, y
+			if t.FlipHorizontal {
+				sx = c.width - 1 - x
+			}
+			if t.FlipVertical {
+				sy = c.height - 1 - y
+			}
+
+			out.pixels[y*c.width+x] = c.at(sx, sy)
+		}
+	}
+
+	for range t.Rotation % 4 {
+		out = out.rotate()
+	}
+
+	return out
+}
+
+// rotate returns a copy of the clipboard rotated 90 degrees clockwise.
+func (c *clipboard) rotate() *clipboard {
+	out := &clipboard{width: c.height, height: c.width, pixels: make([]types.Pixel, len(c.pixels))}
+	for y := range c.height {
+		for x := range c.width {
+			out.pixels[x*out.width+(out.width-1-y)] = c.at(x, y)
+		}
+	}
+
+	return out
+}
+
+// getClipboard returns the user's clipboard or nil if the user hasn't copied anything.
+func (h *Hub) getClipboard(userID string) *clipboard {
+	h.clipboardMutex.Lock()
+	defer h.clipboardMutex.Unlock()
+
+	return h.clipboards[userID]
+}
+
+// setClipboard replaces the user's clipboard and notifies the client of its new size.
+func (h *Hub) setClipboard(client *WSClient, c *clipboard) {
+	h.clipboardMutex.Lock()
+	h.clipboards[client.ID] = c
+	h.clipboardMutex.Unlock()
+
+	sendMessage(client, msg.ClipboardMsg, &msg.ClipboardUpdate{
+		Width:  uint32(c.width),
+		Height: uint32(c.height),
+	})
+}
+
+// selectionBounds returns the part of the rectangle that is inside the canvas as [x0, x1) and [y0, y1).
+func (r *Room) selectionBounds(rect *msg.Rect) (x0, y0, x1, y1 int, err error) {
+	if rect == nil {
+		return 0, 0, 0, 0, ErrInvalidSelection
+	}
+
+	x0, y0 = max(int(rect.X), 0), max(int(rect.Y), 0)
+	x1 = min(int(rect.X)+int(rect.Width), int(r.Width))
+	y1 = min(int(rect.Y)+int(rect.Height), int(r.Height))
+	if x0 >= x1 || y0 >= y1 {
+		return 0, 0, 0, 0, ErrInvalidSelection
+	}
+
+	return x0, y0, x1, y1, nil
+}
+
+// capture copies the pixels of the layer inside the bounds into a new clipboard.
+func (r *Room) capture(layer *types.LoadedLayer, x0, y0, x1, y1 int) *clipboard {
+	c := &clipboard{width: x1 - x0, height: y1 - y0}
+	c.pixels = make([]types.Pixel, 0, c.width*c.height)
+	for y := y0; y < y1; y++ {
+		start := y*int(r.Width) + x0
+		c.pixels = append(c.pixels, layer.PixelData[start:start+c.width]...)
+	}
+
+	return c
+}
+
+// Copy returns the pixels of the layer inside the rectangle.
+func (r *Room) Copy(layerID string, rect *msg.Rect) (*clipboard, error) {
+	x0, y0, x1, y1, err := r.selectionBounds(rect)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.loadStatus != Loaded {
+		return nil, ErrCanvasNotLoaded
+	}
+
+	layer := r.getLayer(layerID)
+	if layer == nil {
+		return nil, ErrLayerNotFound
+	}
+
+	return r.capture(layer, x0, y0, x1, y1), nil
+}
+
+// Cut clears the pixels of the layer inside the rectangle and returns them.
+// When clear is true the pixels are only cleared and nothing is returned.
+func (r *Room) Cut(client *WSClient, edit *msg.SelectionEdit, clear bool) (*clipboard, error) {
+	x0, y0, x1, y1, err := r.selectionBounds(edit.Rect)
+	if err != nil {
+		return nil, err
+	}
+
+	var cut *clipboard
+	err = r.applyEdit(client.ID, edit.LayerId, edit.StrokeId, func(layer *types.LoadedLayer, set func(x, y int, pixel types.Pixel)) error {
+		if !clear {
+			cut = r.capture(layer, x0, y0, x1, y1)
+		}
+
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				set(x, y, types.Pixel{})
+			}
+		}
+
+		return nil
+	})
+
+	return cut, err
+}
+
+// Paste writes the clipboard into the layer with its top left corner at the given position.
+// Fully transparent pixels of the clipboard keep the pixels under them, so a sprite doesn't erase its surroundings.
+// Clipboards can come from another canvas, so colors outside of a locked palette are replaced by the closest palette color.
+func (r *Room) Paste(client *WSClient, paste *msg.Paste, c *clipboard) error {
+	if !r.inReach(paste.Position) || (paste.Transform != nil && paste.Transform.Rotation > 3) {
+		return ErrInvalidToolParams
+	}
+
+	c = c.transform(paste.Transform)
+	return r.applyEdit(client.ID, paste.LayerId, paste.StrokeId, func(_ *types.LoadedLayer, set func(x, y int, pixel types.Pixel)) error {
+		for y := range c.height {
+			for x := range c.width {
+				if p := c.at(x, y); p.A != 0 {
+					set(int(paste.Position.X)+x, int(paste.Position.Y)+y, r.paletteColor(p))
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// MoveSelection moves the pixels of the layer inside the rectangle to the given position, leaving the source area transparent.
+// Like with Paste, fully transparent pixels of the selection keep the pixels under them at the destination.
+// The pixels are lifted, cleared and written back in a single operation so that the move is undone as a whole.
+func (r *Room) MoveSelection(client *WSClient, move *msg.MoveSelection) error {
+	x0, y0, x1, y1, err := r.selectionBounds(move.Rect)
+	if err != nil {
+		return err
+	}
+
+	if !r.inReach(move.Position) || (move.Transform != nil && move.Transform.Rotation > 3) {
+		return ErrInvalidToolParams
+	}
+
+	return r.applyEdit(client.ID, move.LayerId, move.StrokeId, func(layer *types.LoadedLayer, set func(x, y int, pixel types.Pixel)) error {
+		c := r.capture(layer, x0, y0, x1, y1).transform(move.Transform)
+
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				set(x, y, types.Pixel{})
+			}
+		}
+
+		for y := range c.height {
+			for x := range c.width {
+				if p := c.at(x, y); p.A != 0 {
+					set(int(move.Position.X)+x, int(move.Position.Y)+y, r.paletteColor(p))
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// updateSelection broadcasts the selection rectangle of the client so collaborators can see what is being edited.
+func (h *Hub) updateSelection(client *WSClient, payload []byte) {
+	selection := &msg.Selection{}
+	if err := proto.Unmarshal(payload, selection); err != nil {
+		sendError(client, msg.SelectionMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	room := client.GetRoom(selection.RoomId)
+	if room == nil {
+		sendError(client, msg.SelectionMsg, ErrRoomNotFound.Error())
+		return
+	}
+
+	if _, ok := authorize(client, room, msg.SelectionMsg, types.Editor); !ok {
+		return
+	}
+
+	message, err := encodeMessage(msg.SelectionMsg, &msg.SelectionUpdate{
+		RoomId:  room.CanvasID,
+		UserId:  client.ID,
+		ConnId:  client.connID,
+		LayerId: selection.LayerId,
+		Rect:    selection.Rect,
+	})
+	if err != nil {
+		sendError(client, msg.SelectionMsg, ErrMarshallingMsg.Error())
+		return
+	}
+
+	broadcastMessage(client, room, message)
+}
+
+func (h *Hub) copySelection(client *WSClient, payload []byte) {
+	edit := &msg.SelectionEdit{}
+	if err := proto.Unmarshal(payload, edit); err != nil {
+		sendError(client, msg.CopyMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	room := client.GetRoom(edit.RoomId)
+	if room == nil {
+		sendError(client, msg.CopyMsg, ErrRoomNotFound.Error())
+		return
+	}
+
+	// Viewers may copy pixels to paste them into a canvas they can edit
+	if _, ok := authorize(client, room, msg.CopyMsg, types.Viewer); !ok {
+		return
+	}
+
+	c, err := room.Copy(edit.LayerId, edit.Rect)
+	if err != nil {
+		sendSelectionError(client, msg.CopyMsg, err)
+		return
+	}
+
+	h.setClipboard(client, c)
+}
+
+func (h *Hub) cutSelection(client *WSClient, payload []byte) {
+	h.editSelection(client, msg.CutMsg, payload)
+}
+
+func (h *Hub) clearSelection(client *WSClient, payload []byte) {
+	h.editSelection(client, msg.ClearMsg, payload)
+}
+
+func (h *Hub) editSelection(client *WSClient, msgType msg.WSMessageType, payload []byte) {
+	edit := &msg.SelectionEdit{}
+	if err := proto.Unmarshal(payload, edit); err != nil {
+		sendError(client, msgType, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	room := client.GetRoom(edit.RoomId)
+	if room == nil {
+		sendError(client, msgType, ErrRoomNotFound.Error())
+		return
+	}
+
+	if _, ok := authorize(client, room, msgType, types.Editor); !ok {
+		return
+	}
+
+	cut, err := room.Cut(client, edit, msgType == msg.ClearMsg)
+	if err != nil {
+		sendSelectionError(client, msgType, err)
+		return
+	}
+
+	if cut != nil {
+		h.setClipboard(client, cut)
+	}
+}
+
+func (h *Hub) paste(client *WSClient, payload []byte) {
+	paste := &msg.Paste{}
+	if err := proto.Unmarshal(payload, paste); err != nil {
+		sendError(client, msg.PasteMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	room := client.GetRoom(paste.RoomId)
+	if room == nil {
+		sendError(client, msg.PasteMsg, ErrRoomNotFound.Error())
+		return
+	}
+
+	if _, ok := authorize(client, room, msg.PasteMsg, types.Editor); !ok {
+		return
+	}
+
+	c := h.getClipboard(client.ID)
+	if c == nil {
+		sendError(client, msg.PasteMsg, ErrClipboardEmpty.Error())
+		return
+	}
+
+	if err := room.Paste(client, paste, c); err != nil {
+		sendSelectionError(client, msg.PasteMsg, err)
+	}
+}
+
+func (h *Hub) moveSelection(client *WSClient, payload []byte) {
+	move := &msg.MoveSelection{}
+	if err := proto.Unmarshal(payload, move); err != nil {
+		sendError(client, msg.MoveMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	room := client.GetRoom(move.RoomId)
+	if room == nil {
+		sendError(client, msg.MoveMsg, ErrRoomNotFound.Error())
+		return
+	}
+
+	if _, ok := authorize(client, room, msg.MoveMsg, types.Editor); !ok {
+		return
+	}
+
+	if err := room.MoveSelection(client, move); err != nil {
+		sendSelectionError(client, msg.MoveMsg, err)
+	}
+}
+
+// sendSelectionError reports the error of a selection operation back to the client.
+func sendSelectionError(client *WSClient, msgType msg.WSMessageType, err error) {
+	switch {
+	case errors.Is(err, ErrCanvasNotLoaded), errors.Is(err, ErrLayerNotFound), errors.Is(err, ErrLayerLocked),
+		errors.Is(err, ErrInvalidSelection), errors.Is(err, ErrInvalidToolParams):
+		sendError(client, msgType, err.Error())
+	default:
+		sendError(client, msgType, ErrMarshallingMsg.Error())
+	}
+}
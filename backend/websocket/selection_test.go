@@ -0,0 +1,187 @@
+package websocket
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/websocket/msg"
+)
+
+// newTestClipboard creates a clipboard from rows of characters, each pixel gets the character as its red channel.
+func newTestClipboard(rows []string) *clipboard {
+	c := &clipboard{width: len(rows[0]), height: len(rows)}
+	for _, row := range rows {
+		for i := range len(row) {
+			c.pixels = append(c.pixels, types.Pixel{R: row[i], A: 255})
+		}
+	}
+
+	return c
+}
+
+// rows converts the clipboard back into rows of characters.
+func (c *clipboard) rows() []string {
+	rows := make([]string, c.height)
+	for y := range c.height {
+		row := make([]byte, c.width)
+		for x := range c.width {
+			row[x] = c.at(x, y).R
+		}
+		rows[y] = string(row)
+	}
+
+	return rows
+}
+
+func TestClipboardTransform(t *testing.T) {
+	source := []string{
+		"abc",
+		"def",
+	}
+
+	tests := []struct {
+		name      string
+		transform *msg.Transform
+		want      []string
+	}{
+		{name: "no transform", want: source},
+		{name: "empty transform", transform: &msg.Transform{}, want: source},
+		{name: "quarter turn", transform: &msg.Transform{Rotation: 1}, want: []string{"da", "eb", "fc"}},
+		{name: "half turn", transform: &msg.Transform{Rotation: 2}, want: []string{"fed", "cba"}},
+		{name: "three quarter turns", transform: &msg.Transform{Rotation: 3}, want: []string{"cf", "be", "ad"}},
+		{name: "full turn", transform: &msg.Transform{Rotation: 4}, want: source},
+		{name: "horizontal flip", transform: &msg.Transform{FlipHorizontal: true}, want: []string{"cba", "fed"}},
+		{name: "vertical flip", transform: &msg.Transform{FlipVertical: true}, want: []string{"def", "abc"}},
+		{name: "both flips", transform: &msg.Transform{FlipHorizontal: true, FlipVertical: true}, want: []string{"fed", "cba"}},
+		{name: "flip before rotating", transform: &msg.Transform{FlipHorizontal: true, Rotation: 1}, want: []string{"fc", "eb", "da"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClipboard(source)
+			got := c.transform(tt.transform)
+
+			if len(got.pixels) != got.width*got.height {
+				t.Fatalf("transform() returned %d pixels for a %dx%d clipboard", len(got.pixels), got.width, got.height)
+			}
+
+			if rows := got.rows(); !slices.Equal(rows, tt.want) {
+				t.Fatalf("transform() = %q, want %q", rows, tt.want)
+			}
+
+			// Clipboards are shared, so the original must never change
+			if original := c.rows(); !slices.Equal(original, source) {
+				t.Errorf("transform() modified the original clipboard to %q", original)
+			}
+		})
+	}
+}
+
+func TestClipboardRotate(t *testing.T) {
+	tests := []struct {
+		name   string
+		source []string
+		want   []string
+	}{
+		{name: "single pixel", source: []string{"a"}, want: []string{"a"}},
+		{name: "row", source: []string{"abc"}, want: []string{"a", "b", "c"}},
+		{name: "column", source: []string{"a", "b", "c"}, want: []string{"cba"}},
+		{name: "square", source: []string{"ab", "cd"}, want: []string{"ca", "db"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newTestClipboard(tt.source).rotate()
+			if got.width != len(tt.want[0]) || got.height != len(tt.want) {
+				t.Fatalf("rotate() size = %dx%d, want %dx%d", got.width, got.height, len(tt.want[0]), len(tt.want))
+			}
+
+			if rows := got.rows(); !slices.Equal(rows, tt.want) {
+				t.Errorf("rotate() = %q, want %q", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasteAndMoveTransparency(t *testing.T) {
+	tests := []struct {
+		name  string
+		grid  []string
+		apply func(r *Room, client *WSClient) error
+		want  []string
+	}{
+		{
+			name: "paste keeps the pixels under transparent clipboard pixels",
+			grid: []string{
+				"....",
+				"....",
+				"....",
+			},
+			apply: func(r *Room, client *WSClient) error {
+				c := &clipboard{width: 2, height: 2, pixels: parseGrid([]string{"r_", "_r"})}
+				return r.Paste(client, &msg.Paste{LayerId: "layer", Position: &msg.Point{X: 1, Y: 1}}, c)
+			},
+			want: []string{
+				"....",
+				".r..",
+				"..r.",
+			},
+		},
+		{
+			name: "paste writes semi transparent pixels",
+			grid: []string{"...."},
+			apply: func(r *Room, client *WSClient) error {
+				c := &clipboard{width: 1, height: 1, pixels: parseGrid([]string{"h"})}
+				return r.Paste(client, &msg.Paste{LayerId: "layer", Position: &msg.Point{X: 0, Y: 0}}, c)
+			},
+			want: []string{"h..."},
+		},
+		{
+			name: "move clears the source and keeps the pixels under transparent selection pixels",
+			grid: []string{
+				"r_..",
+				"_r..",
+				"....",
+			},
+			apply: func(r *Room, client *WSClient) error {
+				return r.MoveSelection(client, &msg.MoveSelection{
+					LayerId:  "layer",
+					Rect:     &msg.Rect{X: 0, Y: 0, Width: 2, Height: 2},
+					Position: &msg.Point{X: 1, Y: 1},
+				})
+			},
+			want: []string{
+				"__..",
+				"_r..",
+				"..r.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := len(tt.grid[0]), len(tt.grid)
+			layer := &types.LoadedLayer{ID: "layer", Visible: true, Opacity: 100, PixelData: parseGrid(tt.grid)}
+			r := &Room{
+				Width:      uint16(width),
+				Height:     uint16(height),
+				Layers:     []*types.LoadedLayer{layer},
+				loadStatus: Loaded,
+				history:    make(map[string]*userHistory),
+			}
+
+			if err := tt.apply(r, &WSClient{ID: "user"}); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+
+			want := parseGrid(tt.want)
+
+			for i := range want {
+				if layer.PixelData[i] != want[i] {
+					t.Errorf("pixel (%d, %d) = %v, want %v", i%width, i/width, layer.PixelData[i], want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package websocket
+
+import (
+	"github.com/CDavidSV/Pixio/services"
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// tileOf returns the index of the tile containing the pixel.
+func (r *Room) tileOf(pixelIndex int) int {
+	cols, _ := services.TileGrid(r.Width, r.Height)
+	x, y := pixelIndex%int(r.Width), pixelIndex/int(r.Width)
+	return (y/services.TileSize)*cols + x/services.TileSize
+}
+
+// markDirty marks the tile containing the pixel as changed in the layer and in the flattened canvas. Must be called while holding r.mu.
+func (r *Room) markDirty(layerID string, pixelIndex int) {
+	if r.dirtyTiles == nil {
+		r.dirtyTiles = make(map[string]map[int]struct{})
+	}
+	if r.dirtyCanvas == nil {
+		r.dirtyCanvas = make(map[int]struct{})
+	}
+
+	tiles, ok := r.dirtyTiles[layerID]
+	if !ok {
+		tiles = make(map[int]struct{})
+		r.dirtyTiles[layerID] = tiles
+	}
+
+	tile := r.tileOf(pixelIndex)
+	tiles[tile] = struct{}{}
+	r.dirtyCanvas[tile] = struct{}{}
+}
+
+// markCanvasDirty marks every tile of the flattened canvas to be recomposed, used when a layer changes how it is composited.
+// The recomposed canvas is logged as a keyframe since any of its pixels may have changed. Must be called while holding r.mu.
+func (r *Room) markCanvasDirty() {
+	if r.dirtyCanvas == nil {
+		r.dirtyCanvas = make(map[int]struct{})
+	}
+
+	cols, rows := services.TileGrid(r.Width, r.Height)
+	for tile := range cols * rows {
+		r.dirtyCanvas[tile] = struct{}{}
+	}
+	r.logKeyframe(nil)
+}
+
+// takeDirtyTiles copies the pixels of every dirty tile and clears the dirty tiles. Must be called while holding r.mu.
+func (r *Room) takeDirtyTiles() (layerTiles, canvasTiles []types.LoadedTile) {
+	cols, _ := services.TileGrid(r.Width, r.Height)
+
+	for layerID, tiles := range r.dirtyTiles {
+		layer := r.getLayer(layerID)
+		if layer == nil {
+			continue
+		}
+
+		for tile := range tiles {
+			layerTiles = append(layerTiles, types.LoadedTile{
+				LayerID:   layerID,
+				X:         tile % cols,
+				Y:         tile / cols,
+				PixelData: services.ExtractTile(layer.PixelData, r.Width, r.Height, tile%cols, tile/cols),
+			})
+		}
+	}
+
+	for tile := range r.dirtyCanvas {
+		canvasTiles = append(canvasTiles, types.LoadedTile{
+			X:         tile % cols,
+			Y:         tile / cols,
+			PixelData: r.flattenTile(tile%cols, tile/cols),
+		})
+	}
+
+	r.dirtyTiles = nil
+	r.dirtyCanvas = nil
+	return layerTiles, canvasTiles
+}
+
+// restoreDirtyTiles marks the tiles as dirty again after they failed to be saved. Must be called while holding r.mu.
+func (r *Room) restoreDirtyTiles(layerTiles, canvasTiles []types.LoadedTile) {
+	cols, _ := services.TileGrid(r.Width, r.Height)
+
+	for _, tile := range layerTiles {
+		x, y, _, _ := services.TileBounds(r.Width, r.Height, tile.X, tile.Y)
+		r.markDirty(tile.LayerID, y*int(r.Width)+x)
+	}
+
+	if r.dirtyCanvas == nil {
+		r.dirtyCanvas = make(map[int]struct{})
+	}
+
+	for _, tile := range canvasTiles {
+		r.dirtyCanvas[tile.Y*cols+tile.X] = struct{}{}
+	}
+}
+
+// flattenTile composites the layers of the room inside the tile. Must be called while holding r.mu.
+func (r *Room) flattenTile(tileX, tileY int) []types.Pixel {
+	_, _, w, h := services.TileBounds(r.Width, r.Height, tileX, tileY)
+
+	layers := make([]types.LoadedLayer, len(r.Layers))
+	for i, layer := range r.Layers {
+		layers[i] = *layer
+		layers[i].PixelData = services.ExtractTile(layer.PixelData, r.Width, r.Height, tileX, tileY)
+	}
+
+	return r.hub.services.CanvasService.FlattenLayers(uint16(w), uint16(h), layers)
+}
+
+// flatten composites every layer of the room. Must be called while holding r.mu.
+func (r *Room) flatten() []types.Pixel {
+	layers := make([]types.LoadedLayer, len(r.Layers))
+	for i, layer := range r.Layers {
+		layers[i] = *layer
+	}
+
+	return r.hub.services.CanvasService.FlattenLayers(r.Width, r.Height, layers)
+}
+
+// CanvasTile returns the flattened pixels of the tile from the live canvas room.
+// Returns false if the canvas is not loaded in a room, in which case the stored tile is up to date.
+func (h *Hub) CanvasTile(canvasID string, tileX, tileY int) ([]types.Pixel, bool) {
+	room := h.getRoom(canvasID)
+	if room == nil {
+		return nil, false
+	}
+
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	if room.loadStatus != Loaded || !services.ValidTile(room.Width, room.Height, tileX, tileY) {
+		return nil, false
+	}
+
+	return room.flattenTile(tileX, tileY), true
+}
+
+// FlattenedCanvas returns the flattened pixels of the live canvas room.
+// Returns false if the canvas is not loaded in a room, in which case the stored canvas is up to date.
+func (h *Hub) FlattenedCanvas(canvasID string) ([]types.Pixel, bool) {
+	room := h.getRoom(canvasID)
+	if room == nil {
+		return nil, false
+	}
+
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+
+	if room.loadStatus != Loaded {
+		return nil, false
+	}
+
+	return room.flatten(), true
+}
@@ -0,0 +1,105 @@
+package websocket
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/CDavidSV/Pixio/services"
+	"github.com/CDavidSV/Pixio/types"
+)
+
+// newTileTestRoom returns a loaded 130x70 room, which has 3x2 tiles with smaller tiles on the right and bottom edges.
+func newTileTestRoom() (*Room, *types.LoadedLayer) {
+	layer := &types.LoadedLayer{ID: "layer", Visible: true, Opacity: 100, PixelData: make([]types.Pixel, 130*70)}
+	r := &Room{
+		Width:      130,
+		Height:     70,
+		Layers:     []*types.LoadedLayer{layer},
+		loadStatus: Loaded,
+		hub:        &Hub{services: &services.Services{CanvasService: &services.CanvasService{}}},
+	}
+
+	return r, layer
+}
+
+func TestDirtyTiles(t *testing.T) {
+	red := types.Pixel{R: 255, A: 255}
+
+	tests := []struct {
+		name      string
+		pixels    [][2]int // Pixels changed on the layer
+		wantTiles []int
+	}{
+		{name: "no changes"},
+		{name: "single pixel", pixels: [][2]int{{1, 1}}, wantTiles: []int{0}},
+		{name: "pixels of the same tile", pixels: [][2]int{{0, 0}, {63, 63}}, wantTiles: []int{0}},
+		{name: "edge tiles", pixels: [][2]int{{129, 0}, {129, 69}, {64, 64}}, wantTiles: []int{2, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, layer := newTileTestRoom()
+			for _, p := range tt.pixels {
+				index := p[1]*int(r.Width) + p[0]
+				layer.PixelData[index] = red
+				r.markDirty(layer.ID, index)
+			}
+
+			layerTiles, canvasTiles := r.takeDirtyTiles()
+			if r.dirtyTiles != nil || r.dirtyCanvas != nil {
+				t.Error("takeDirtyTiles() didn't clear the dirty tiles")
+			}
+
+			tileIndexes := func(tiles []types.LoadedTile) []int {
+				indexes := make([]int, len(tiles))
+				for i, tile := range tiles {
+					indexes[i] = tile.Y*3 + tile.X
+				}
+				slices.Sort(indexes)
+				return indexes
+			}
+
+			if got := tileIndexes(layerTiles); !slices.Equal(got, tt.wantTiles) {
+				t.Errorf("layer tiles = %v, want %v", got, tt.wantTiles)
+			}
+			if got := tileIndexes(canvasTiles); !slices.Equal(got, tt.wantTiles) {
+				t.Errorf("canvas tiles = %v, want %v", got, tt.wantTiles)
+			}
+
+			// The taken tiles hold the pixels of the layer, and with a single opaque layer the flattened canvas is the same
+			for _, tiles := range [][]types.LoadedTile{layerTiles, canvasTiles} {
+				for _, tile := range tiles {
+					want := services.ExtractTile(layer.PixelData, r.Width, r.Height, tile.X, tile.Y)
+					if !slices.Equal(tile.PixelData, want) {
+						t.Errorf("tile (%d, %d) doesn't hold the pixels of the layer", tile.X, tile.Y)
+					}
+				}
+			}
+
+			// Tiles that failed to be saved are dirty again
+			r.restoreDirtyTiles(layerTiles, canvasTiles)
+			if got := slices.Sorted(maps.Keys(r.dirtyTiles[layer.ID])); !slices.Equal(got, tt.wantTiles) {
+				t.Errorf("restored layer tiles = %v, want %v", got, tt.wantTiles)
+			}
+			if got := slices.Sorted(maps.Keys(r.dirtyCanvas)); !slices.Equal(got, tt.wantTiles) {
+				t.Errorf("restored canvas tiles = %v, want %v", got, tt.wantTiles)
+			}
+		})
+	}
+}
+
+func TestTakeDirtyTilesOfDeletedLayer(t *testing.T) {
+	r, layer := newTileTestRoom()
+	r.markDirty(layer.ID, 0)
+	r.Layers = nil
+
+	// The canvas tile must still be recomposed since the layer is no longer part of it
+	layerTiles, canvasTiles := r.takeDirtyTiles()
+	if len(layerTiles) != 0 {
+		t.Errorf("takeDirtyTiles() returned %d tiles of a deleted layer", len(layerTiles))
+	}
+	if len(canvasTiles) != 1 {
+		t.Errorf("takeDirtyTiles() returned %d canvas tiles, want 1", len(canvasTiles))
+	}
+}
@@ -0,0 +1,403 @@
+package websocket
+
+import (
+	"errors"
+
+	"github.com/CDavidSV/Pixio/config"
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/websocket/msg"
+	"google.golang.org/protobuf/proto"
+)
+
+// toolFunc paints on the layer by calling plot for every pixel the tool covers.
+// Pixels outside of the canvas are ignored by plot, so tools don't need to clip.
+type toolFunc func(layer *types.LoadedLayer, plot func(x, y int)) error
+
+// editFunc changes the layer by calling set for every pixel that gets a new value.
+// Pixels outside of the canvas are ignored by set.
+type editFunc func(layer *types.LoadedLayer, set func(x, y int, pixel types.Pixel)) error
+
+// applyTool runs the tool against the layer painting every covered pixel with the given color.
+func (r *Room) applyTool(client *WSClient, layerID, strokeID string, color *msg.Color, draw toolFunc) error {
+	pixel, ok := colorFromMessage(color)
+	if !ok {
+		return ErrInvalidToolParams
+	}
+
+	return r.applyEdit(client.ID, layerID, strokeID, func(layer *types.LoadedLayer, set func(x, y int, pixel types.Pixel)) error {
+		if !r.inPalette(pixel) {
+			return ErrColorNotInPalette
+		}
+
+		return draw(layer, func(x, y int) { set(x, y, pixel) })
+	})
+}
+
+// applyEdit runs the edit against the layer and broadcasts the resulting pixel diff as a single operation to every client in the room, including the sender.
+func (r *Room) applyEdit(userID, layerID, strokeID string, edit editFunc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.loadStatus != Loaded {
+		return ErrCanvasNotLoaded
+	}
+
+	layer := r.getLayer(layerID)
+	if layer == nil {
+		return ErrLayerNotFound
+	}
+
+	if layer.Locked {
+		return ErrLayerLocked
+	}
+
+	var changes []pixelChange
+	positions := make(map[int]int) // pixel index -> position in changes
+	set := func(x, y int, pixel types.Pixel) {
+		if x < 0 || y < 0 || x >= int(r.Width) || y >= int(r.Height) {
+			return
+		}
+
+		// Pixels that already have the value are skipped, pixels set more than once keep a single change
+		index := y*int(r.Width) + x
+		if index >= len(layer.PixelData) || layer.PixelData[index] == pixel {
+			return
+		}
+
+		if i, exists := positions[index]; exists {
+			changes[i].after = pixel
+		} else {
+			positions[index] = len(changes)
+			changes = append(changes, pixelChange{index: index, before: layer.PixelData[index], after: pixel})
+		}
+		layer.PixelData[index] = pixel
+		r.markDirty(layer.ID, index)
+	}
+
+	if err := edit(layer, set); err != nil {
+		return err
+	}
+
+	// A pixel might have been set back to its original value
+	updates := make([]*msg.PixelUpdate, 0, len(changes))
+	kept := changes[:0]
+	for _, c := range changes {
+		if c.before == c.after {
+			continue
+		}
+
+		kept = append(kept, c)
+		updates = append(updates, r.pixelUpdate(c.index, c.after))
+	}
+	changes = kept
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	r.recordStroke(userID, layer.ID, strokeID, changes)
+	r.recordChanges(userID, changes)
+
+	_, err := r.commit(nil, msg.SetPixelsMsg, func(seq uint64) proto.Message {
+		return &msg.PixelsUpdate{
+			UserId:   userID,
+			Pixels:   updates,
+			Revision: seq,
+			LayerId:  layer.ID,
+		}
+	})
+
+	return err
+}
+
+// inReach reports whether the point is close enough to the canvas to be drawn with.
+// Points may be outside of the canvas so that shapes can be partially drawn, but not further than the canvas size.
+func (r *Room) inReach(p *msg.Point) bool {
+	if p == nil {
+		return false
+	}
+
+	w, h := int(r.Width), int(r.Height)
+	return int(p.X) >= -w && int(p.X) < 2*w && int(p.Y) >= -h && int(p.Y) < 2*h
+}
+
+// FloodFill fills the pixels matching the color at (x, y) within the given tolerance.
+// Contiguous fills only spread to neighbouring pixels, otherwise every matching pixel of the layer is filled.
+func (r *Room) FloodFill(client *WSClient, fill *msg.FloodFill) error {
+	if fill.X >= uint32(r.Width) || fill.Y >= uint32(r.Height) || fill.Tolerance > 255 {
+		return ErrInvalidToolParams
+	}
+
+	return r.applyTool(client, fill.LayerId, fill.StrokeId, fill.Color, func(layer *types.LoadedLayer, plot func(x, y int)) error {
+		width, height := int(r.Width), int(r.Height)
+		target := layer.PixelData[int(fill.Y)*width+int(fill.X)]
+
+		matches := func(index int) bool {
+			return colorDistance(layer.PixelData[index], target) <= int(fill.Tolerance)
+		}
+
+		if !fill.Contiguous {
+			for index := range layer.PixelData {
+				if matches(index) {
+					plot(index%width, index/width)
+				}
+			}
+			return nil
+		}
+
+		visited := make([]bool, len(layer.PixelData))
+		start := int(fill.Y)*width + int(fill.X)
+		visited[start] = true
+		queue := []int{start}
+
+		for len(queue) > 0 {
+			index := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+
+			x, y := index%width, index/width
+			plot(x, y)
+
+			neighbours := [4][2]int{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}}
+			for _, n := range neighbours {
+				if n[0] < 0 || n[1] < 0 || n[0] >= width || n[1] >= height {
+					continue
+				}
+
+				next := n[1]*width + n[0]
+				if visited[next] || !matches(next) {
+					continue
+				}
+
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+
+		return nil
+	})
+}
+
+// DrawLine draws a one pixel wide line between two points using Bresenham's algorithm.
+func (r *Room) DrawLine(client *WSClient, line *msg.DrawLine) error {
+	if !r.inReach(line.From) || !r.inReach(line.To) {
+		return ErrInvalidToolParams
+	}
+
+	return r.applyTool(client, line.LayerId, line.StrokeId, line.Color, func(_ *types.LoadedLayer, plot func(x, y int)) error {
+		bresenham(int(line.From.X), int(line.From.Y), int(line.To.X), int(line.To.Y), plot)
+		return nil
+	})
+}
+
+// DrawShape draws a rectangle or an ellipse inside the bounding box defined by two corners.
+func (r *Room) DrawShape(client *WSClient, shape *msg.DrawShape) error {
+	if shape.From == nil || shape.To == nil {
+		return ErrInvalidToolParams
+	}
+
+	x0, x1 := int(min(shape.From.X, shape.To.X)), int(max(shape.From.X, shape.To.X))
+	y0, y1 := int(min(shape.From.Y, shape.To.Y)), int(max(shape.From.Y, shape.To.Y))
+
+	var inside func(x, y int) bool
+	switch shape.Shape {
+	case msg.ShapeType_RECTANGLE:
+		inside = func(x, y int) bool {
+			return x >= x0 && x <= x1 && y >= y0 && y <= y1
+		}
+	case msg.ShapeType_ELLIPSE:
+		cx, cy := float64(x0+x1+1)/2, float64(y0+y1+1)/2
+		rx, ry := float64(x1-x0+1)/2, float64(y1-y0+1)/2
+		inside = func(x, y int) bool {
+			dx := (float64(x) + 0.5 - cx) / rx
+			dy := (float64(y) + 0.5 - cy) / ry
+			return dx*dx+dy*dy <= 1
+		}
+	default:
+		return ErrInvalidToolParams
+	}
+
+	return r.applyTool(client, shape.LayerId, shape.StrokeId, shape.Color, func(_ *types.LoadedLayer, plot func(x, y int)) error {
+		// Only the part of the bounding box that overlaps the canvas needs to be visited
+		for y := max(y0, 0); y <= min(y1, int(r.Height)-1); y++ {
+			for x := max(x0, 0); x <= min(x1, int(r.Width)-1); x++ {
+				if !inside(x, y) {
+					continue
+				}
+
+				if shape.Filled || !inside(x-1, y) || !inside(x+1, y) || !inside(x, y-1) || !inside(x, y+1) {
+					plot(x, y)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// BrushStamp stamps a square or circular brush of the given size centered at each point.
+func (r *Room) BrushStamp(client *WSClient, stamp *msg.BrushStamp) error {
+	if stamp.Size == 0 || stamp.Size > uint32(config.ToolMaxBrushSize) {
+		return ErrInvalidToolParams
+	}
+
+	if len(stamp.Points) == 0 || len(stamp.Points) > config.ToolMaxStampPoints {
+		return ErrInvalidToolParams
+	}
+
+	for _, p := range stamp.Points {
+		if !r.inReach(p) {
+			return ErrInvalidToolParams
+		}
+	}
+
+	if stamp.Shape != msg.BrushShape_SQUARE && stamp.Shape != msg.BrushShape_CIRCLE {
+		return ErrInvalidToolParams
+	}
+
+	// Offsets of the brush pixels relative to the point, even sizes lean towards the bottom right
+	size := int(stamp.Size)
+	center := float64(size-1) / 2
+	radius := float64(size) / 2
+
+	var brush [][2]int
+	for dy := range size {
+		for dx := range size {
+			if stamp.Shape == msg.BrushShape_CIRCLE {
+				fx, fy := float64(dx)-center, float64(dy)-center
+				if fx*fx+fy*fy > radius*radius {
+					continue
+				}
+			}
+
+			brush = append(brush, [2]int{dx - (size-1)/2, dy - (size-1)/2})
+		}
+	}
+
+	return r.applyTool(client, stamp.LayerId, stamp.StrokeId, stamp.Color, func(_ *types.LoadedLayer, plot func(x, y int)) error {
+		for _, p := range stamp.Points {
+			for _, offset := range brush {
+				plot(int(p.X)+offset[0], int(p.Y)+offset[1])
+			}
+		}
+
+		return nil
+	})
+}
+
+// bresenham calls plot for every pixel of the line between (x0, y0) and (x1, y1).
+func bresenham(x0, y0, x1, y1 int, plot func(x, y int)) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+	for {
+		plot(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// colorDistance returns the largest difference between the channels of two pixels.
+func colorDistance(a, b types.Pixel) int {
+	return max(
+		abs(int(a.R)-int(b.R)),
+		abs(int(a.G)-int(b.G)),
+		abs(int(a.B)-int(b.B)),
+		abs(int(a.A)-int(b.A)),
+	)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+func (h *Hub) floodFill(client *WSClient, payload []byte) {
+	fill := &msg.FloodFill{}
+	if err := proto.Unmarshal(payload, fill); err != nil {
+		sendError(client, msg.FloodFillMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	h.useTool(client, msg.FloodFillMsg, fill.RoomId, func(room *Room) error {
+		return room.FloodFill(client, fill)
+	})
+}
+
+func (h *Hub) drawLine(client *WSClient, payload []byte) {
+	line := &msg.DrawLine{}
+	if err := proto.Unmarshal(payload, line); err != nil {
+		sendError(client, msg.DrawLineMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	h.useTool(client, msg.DrawLineMsg, line.RoomId, func(room *Room) error {
+		return room.DrawLine(client, line)
+	})
+}
+
+func (h *Hub) drawShape(client *WSClient, payload []byte) {
+	shape := &msg.DrawShape{}
+	if err := proto.Unmarshal(payload, shape); err != nil {
+		sendError(client, msg.DrawShapeMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	h.useTool(client, msg.DrawShapeMsg, shape.RoomId, func(room *Room) error {
+		return room.DrawShape(client, shape)
+	})
+}
+
+func (h *Hub) brushStamp(client *WSClient, payload []byte) {
+	stamp := &msg.BrushStamp{}
+	if err := proto.Unmarshal(payload, stamp); err != nil {
+		sendError(client, msg.BrushStampMsg, ErrUnmarshallingMsg.Error())
+		return
+	}
+
+	h.useTool(client, msg.BrushStampMsg, stamp.RoomId, func(room *Room) error {
+		return room.BrushStamp(client, stamp)
+	})
+}
+
+// useTool checks that the client can edit the room before running the tool and reports any error back to the client.
+func (h *Hub) useTool(client *WSClient, msgType msg.WSMessageType, roomID string, use func(room *Room) error) {
+	room := client.GetRoom(roomID)
+	if room == nil {
+		sendError(client, msgType, ErrRoomNotFound.Error())
+		return
+	}
+
+	if _, ok := authorize(client, room, msgType, types.Editor); !ok {
+		return
+	}
+
+	if err := use(room); err != nil {
+		if errors.Is(err, ErrCanvasNotLoaded) || errors.Is(err, ErrLayerNotFound) || errors.Is(err, ErrLayerLocked) || errors.Is(err, ErrInvalidToolParams) || errors.Is(err, ErrColorNotInPalette) {
+			sendError(client, msgType, err.Error())
+		} else {
+			sendError(client, msgType, ErrMarshallingMsg.Error())
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package websocket
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/websocket/msg"
+)
+
+func TestBresenham(t *testing.T) {
+	tests := []struct {
+		name           string
+		x0, y0, x1, y1 int
+		want           [][2]int
+	}{
+		{name: "single point", x0: 2, y0: 2, x1: 2, y1: 2, want: [][2]int{{2, 2}}},
+		{name: "horizontal", x0: 0, y0: 1, x1: 3, y1: 1, want: [][2]int{{0, 1}, {1, 1}, {2, 1}, {3, 1}}},
+		{name: "vertical upwards", x0: 1, y0: 2, x1: 1, y1: 0, want: [][2]int{{1, 2}, {1, 1}, {1, 0}}},
+		{name: "diagonal", x0: 0, y0: 0, x1: 2, y1: 2, want: [][2]int{{0, 0}, {1, 1}, {2, 2}}},
+		{name: "shallow", x0: 0, y0: 0, x1: 3, y1: 2, want: [][2]int{{0, 0}, {1, 1}, {2, 1}, {3, 2}}},
+		{name: "shallow reversed", x0: 3, y0: 2, x1: 0, y1: 0, want: [][2]int{{3, 2}, {2, 1}, {1, 1}, {0, 0}}},
+		{name: "steep", x0: 0, y0: 0, x1: 1, y1: 3, want: [][2]int{{0, 0}, {0, 1}, {1, 2}, {1, 3}}},
+		{name: "outside of the canvas", x0: -1, y0: 0, x1: 1, y1: 0, want: [][2]int{{-1, 0}, {0, 0}, {1, 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][2]int
+			bresenham(tt.x0, tt.y0, tt.x1, tt.y1, func(x, y int) {
+				got = append(got, [2]int{x, y})
+			})
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("bresenham(%d, %d, %d, %d) = %v, want %v", tt.x0, tt.y0, tt.x1, tt.y1, got, tt.want)
+			}
+		})
+	}
+}
+
+// testColors maps the characters used by test grids to pixels.
+var testColors = map[byte]types.Pixel{
+	'.': {R: 255, G: 255, B: 255, A: 255},
+	'#': {A: 255},
+	'g': {R: 20, G: 20, B: 20, A: 255},
+	'r': {R: 255, A: 255},
+	'h': {R: 255, A: 128},
+	'_': {},
+}
+
+// parseGrid converts rows of characters into the pixels of a canvas.
+func parseGrid(rows []string) []types.Pixel {
+	pixels := make([]types.Pixel, 0, len(rows)*len(rows[0]))
+	for _, row := range rows {
+		for i := range len(row) {
+			pixels = append(pixels, testColors[row[i]])
+		}
+	}
+
+	return pixels
+}
+
+func TestFloodFill(t *testing.T) {
+	grid := []string{
+		"##...",
+		"#..#.",
+		"###g.",
+		"..#..",
+	}
+	red := &msg.Color{R: 255, A: 255}
+
+	tests := []struct {
+		name    string
+		fill    *msg.FloodFill
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "contiguous",
+			fill: &msg.FloodFill{X: 2, Y: 0, Color: red, Contiguous: true},
+			want: []string{
+				"##rrr",
+				"#rr#r",
+				"###gr",
+				"..#rr",
+			},
+		},
+		{
+			name: "every matching pixel",
+			fill: &msg.FloodFill{X: 2, Y: 0, Color: red},
+			want: []string{
+				"##rrr",
+				"#rr#r",
+				"###gr",
+				"rr#rr",
+			},
+		},
+		{
+			name: "contiguous without tolerance",
+			fill: &msg.FloodFill{X: 0, Y: 0, Color: red, Contiguous: true},
+			want: []string{
+				"rr...",
+				"r..#.",
+				"rrrg.",
+				"..r..",
+			},
+		},
+		{
+			name: "contiguous with tolerance",
+			fill: &msg.FloodFill{X: 0, Y: 0, Color: red, Contiguous: true, Tolerance: 20},
+			want: []string{
+				"rr...",
+				"r..r.",
+				"rrrr.",
+				"..r..",
+			},
+		},
+		{
+			name: "same color",
+			fill: &msg.FloodFill{X: 2, Y: 0, Color: &msg.Color{R: 255, G: 255, B: 255, A: 255}, Contiguous: true},
+			want: grid,
+		},
+		{
+			name:    "outside of the canvas",
+			fill:    &msg.FloodFill{X: 5, Y: 0, Color: red},
+			want:    grid,
+			wantErr: ErrInvalidToolParams,
+		},
+		{
+			name:    "tolerance out of range",
+			fill:    &msg.FloodFill{X: 0, Y: 0, Color: red, Tolerance: 256},
+			want:    grid,
+			wantErr: ErrInvalidToolParams,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layer := &types.LoadedLayer{ID: "layer", Visible: true, Opacity: 100, PixelData: parseGrid(grid)}
+			r := &Room{
+				Width:      5,
+				Height:     4,
+				Layers:     []*types.LoadedLayer{layer},
+				loadStatus: Loaded,
+				history:    make(map[string]*userHistory),
+			}
+
+			tt.fill.LayerId = layer.ID
+			err := r.FloodFill(&WSClient{ID: "user"}, tt.fill)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FloodFill() error = %v, want %v", err, tt.wantErr)
+			}
+
+			want := parseGrid(tt.want)
+			for i := range want {
+				if layer.PixelData[i] != want[i] {
+					t.Errorf("pixel (%d, %d) = %v, want %v", i%5, i/5, layer.PixelData[i], want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package websocket
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/CDavidSV/Pixio/config"
+	"github.com/CDavidSV/Pixio/types"
+	"github.com/CDavidSV/Pixio/websocket/msg"
+)
+
+// countEdit counts a committed operation towards the next automatic version. Must be called while holding r.mu.
+func (r *Room) countEdit() {
+	r.editCount++
+
+	// Checked on every multiple so that a failed version is retried after the next batch of edits instead of on every edit
+	if r.editCount%config.VersionEditInterval == 0 {
+		r.startAutoVersion()
+	}
+}
+
+// checkVersionInterval saves a version if the room has edits and no version was saved for config.VersionTimeInterval.
+func (r *Room) checkVersionInterval() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.editCount > 0 && time.Since(r.lastVersionAt) >= config.VersionTimeInterval {
+		r.startAutoVersion()
+	}
+}
+
+// checkEvictionVersion saves a version before the room is evicted if it has edits that are not part of a version yet.
+func (r *Room) checkEvictionVersion() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.editCount > 0 {
+		r.startAutoVersion()
+	}
+}
+
+// startAutoVersion saves the current layers as a version in the background. Must be called while holding r.mu.
+func (r *Room) startAutoVersion() {
+	if r.versioning || r.loadStatus != Loaded {
+		return
+	}
+
+	layers, editCount := r.takeVersionSnapshot()
+	r.versioning = true
+
+	go func() {
+		_, err := r.hub.services.CanvasService.CreateVersion(r.CanvasID, r.Width, r.Height, layers, editCount, "", "")
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.versioning = false
+		if err != nil {
+			slog.Error("Failed to save automatic version", "canvasID", r.CanvasID, "error", err)
+			r.editCount += editCount
+		}
+	}()
+}
+
+// takeVersionSnapshot copies the layers for a new version and starts counting edits for the next one. Must be called while holding r.mu.
+func (r *Room) takeVersionSnapshot() ([]types.LoadedLayer, int) {
+	editCount := r.editCount
+	r.editCount = 0
+	r.lastVersionAt = time.Now()
+
+	return r.cloneLayers(), editCount
+}
+
+// restore replaces the room's layers with the layers of a restored version and sends the new snapshot to every client.
+// Must be called while holding r.mu.
+func (r *Room) restore(layers []types.LoadedLayer, flattened []types.Pixel, versionID, userID string) {
+	r.Layers = make([]*types.LoadedLayer, len(layers))
+	for i := range layers {
+		r.Layers[i] = &layers[i]
+	}
+
+	// The restored layers are already saved, and undoing strokes made before the restore would mix both versions
+	r.dirtyTiles = nil
+	r.dirtyCanvas = nil
+	r.history = make(map[string]*userHistory)
+	r.editCount = 0
+	r.lastVersionAt = time.Now()
+
+	// The operation log is still saved by the next flush, after the operations from before the restore
+	r.logKeyframe(flattened)
+
+	// Operations from before the restore can't be replayed on top of it, reconnecting clients need the new snapshot
+	r.opLog = nil
+	r.revision++
+
+	snapshot, err := r.newSnapshot(layers, r.Palette, r.PaletteLocked, r.revision)
+	if err != nil {
+		slog.Error("Failed to create canvas snapshot", "canvasID", r.CanvasID, "error", err)
+		return
+	}
+
+	snapshotMsg, err := encodeMessage(msg.CanvasSnapshotMsg, snapshot)
+	if err != nil {
+		slog.Error("Failed to encode canvas snapshot", "canvasID", r.CanvasID, "error", err)
+		return
+	}
+
+	restoredMsg, err := encodeMessage(msg.VersionRestoreMsg, &msg.VersionRestored{
+		RoomId:     r.CanvasID,
+		VersionId:  versionID,
+		RestoredBy: userID,
+		Revision:   r.revision,
+	})
+	if err != nil {
+		slog.Error("Failed to encode version restored message", "canvasID", r.CanvasID, "error", err)
+		return
+	}
+
+	for _, c := range r.Clients {
+		c.WSClient.enqueue(snapshotMsg, false)
+		c.WSClient.enqueue(restoredMsg, false)
+	}
+}
+
+// SaveVersion saves the current state of the canvas as a version with the given label.
+// Live canvases are saved from the room so that the version includes the changes that were not flushed yet.
+func (h *Hub) SaveVersion(canvasID, label, userID string) (types.Version, error) {
+	room := h.getRoom(canvasID)
+	if room != nil {
+		room.mu.Lock()
+		if room.loadStatus == Loaded {
+			layers, editCount := room.takeVersionSnapshot()
+			room.mu.Unlock()
+
+			version, err := h.services.CanvasService.CreateVersion(canvasID, room.Width, room.Height, layers, editCount, label, userID)
+			if err != nil {
+				room.mu.Lock()
+				room.editCount += editCount
+				room.mu.Unlock()
+			}
+
+			return version, err
+		}
+		room.mu.Unlock()
+	}
+
+	return h.services.CanvasService.SaveVersion(canvasID, label, userID)
+}
+
+// RestoreVersion replaces the layers of the canvas with the layers of the version.
+// Restoring a live canvas pushes the restored snapshot to every client in the room.
+func (h *Hub) RestoreVersion(canvasID, versionID, userID string) error {
+	// A room that is created or loading while the layers are replaced waits for the restore before loading them
+	unlock := h.lockCanvas(canvasID)
+	defer unlock()
+
+	room := h.getRoom(canvasID)
+	if room == nil {
+		_, flattened, err := h.services.CanvasService.RestoreVersion(canvasID, versionID)
+		if err != nil {
+			return err
+		}
+
+		return h.services.CanvasService.LogKeyframe(canvasID, flattened)
+	}
+
+	// Holding the flush lock prevents a flush from overwriting the restored layers, while the room keeps accepting edits.
+	// Edits made before the layers are swapped are discarded by the restore, the same as if they were made before it.
+	room.flushMu.Lock()
+	defer room.flushMu.Unlock()
+
+	layers, flattened, err := h.services.CanvasService.RestoreVersion(canvasID, versionID)
+	if err != nil {
+		return err
+	}
+
+	room.mu.Lock()
+	loaded := room.loadStatus == Loaded
+	if loaded {
+		room.restore(layers, flattened, versionID, userID)
+	}
+	room.mu.Unlock()
+
+	if loaded {
+		return nil
+	}
+
+	return h.services.CanvasService.LogKeyframe(canvasID, flattened)
+}
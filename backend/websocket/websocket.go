@@ -4,6 +4,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -33,6 +34,19 @@ type Hub struct {
 	handlers  map[string]HandlerFunc
 	rooms     map[string]*Room
 	roomMutex sync.RWMutex
+	sendStats SendStats
+
+	clipboards     map[string]*clipboard // userID -> clipboard
+	clipboardMutex sync.Mutex
+
+	canvasLocks     map[string]*canvasLock // canvasID -> lock on the stored layers of the canvas
+	canvasLockMutex sync.Mutex
+}
+
+// canvasLock is held while the stored layers of a canvas are loaded into its room or replaced without going through the room.
+type canvasLock struct {
+	mu   sync.Mutex
+	refs int // Number of goroutines holding or waiting for the lock
 }
 
 func NewWebsocketHub(queries *data.Queries, services *services.Services) *Hub {
@@ -42,6 +56,9 @@ func NewWebsocketHub(queries *data.Queries, services *services.Services) *Hub {
 		services: services,
 		handlers: make(map[string]HandlerFunc),
 		rooms:    make(map[string]*Room),
+
+		clipboards:  make(map[string]*clipboard),
+		canvasLocks: make(map[string]*canvasLock),
 	}
 	hub.registerHandlers()
 
@@ -52,6 +69,21 @@ func (h *Hub) registerHandlers() {
 	h.handlers[string(msg.MousePosUpdateMsg)] = h.updateCursorPosition
 	h.handlers[string(msg.JoinRoomMsg)] = h.joinRoom
 	h.handlers[string(msg.LeaveRoomMsg)] = h.leaveRoom
+	h.handlers[string(msg.SetPixelsMsg)] = h.setPixels
+	h.handlers[string(msg.UndoMsg)] = h.undo
+	h.handlers[string(msg.RedoMsg)] = h.redo
+	h.handlers[string(msg.FloodFillMsg)] = h.floodFill
+	h.handlers[string(msg.DrawLineMsg)] = h.drawLine
+	h.handlers[string(msg.DrawShapeMsg)] = h.drawShape
+	h.handlers[string(msg.BrushStampMsg)] = h.brushStamp
+	h.handlers[string(msg.SelectionMsg)] = h.updateSelection
+	h.handlers[string(msg.CopyMsg)] = h.copySelection
+	h.handlers[string(msg.CutMsg)] = h.cutSelection
+	h.handlers[string(msg.ClearMsg)] = h.clearSelection
+	h.handlers[string(msg.PasteMsg)] = h.paste
+	h.handlers[string(msg.MoveMsg)] = h.moveSelection
+	h.handlers[string(msg.PaletteEditMsg)] = h.editPalette
+	h.handlers[string(msg.ChatMsg)] = h.sendChatMessage
 }
 
 func (h *Hub) WSHanlder(w http.ResponseWriter, r *http.Request) {
@@ -69,7 +101,7 @@ func (h *Hub) WSHanlder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	client := NewClient(userID, conn)
+	client := NewClient(userID, conn, &h.sendStats)
 
 	err = h.waitForAuth(conn, userID)
 	if err != nil {
@@ -83,7 +115,8 @@ func (h *Hub) WSHanlder(w http.ResponseWriter, r *http.Request) {
 			sendError(client, msg.ErrorMsg, ErrUnexpected.Error())
 		}
 
-		conn.Close()
+		client.Close(websocket.ClosePolicyViolation, err.Error())
+		client.closeConn()
 		return
 	}
 
@@ -97,21 +130,50 @@ func (h *Hub) WSHanlder(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Hub) writePump(c *WSClient) {
-	for data := range c.send {
-		c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-		err := c.conn.WriteMessage(websocket.BinaryMessage, data)
-		if err != nil {
-			slog.Error("Error writing message to client: ", "error", err.Error())
-			break
+	ticker := time.NewTicker(config.WSPingInterval)
+	defer ticker.Stop()
+	defer c.closeConn()
+
+	for {
+		select {
+		case <-ticker.C:
+			// The ping carries the time it was sent so that the round-trip time can be measured when the pong arrives
+			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			err := c.conn.WriteMessage(websocket.PingMessage, []byte(strconv.FormatInt(time.Now().UnixNano(), 10)))
+			if err != nil {
+				slog.Error("Error sending ping to client: ", "error", err.Error())
+				c.Close(websocket.CloseGoingAway, "")
+				return
+			}
+		case data := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			err := c.conn.WriteMessage(websocket.BinaryMessage, data)
+			if err != nil {
+				slog.Error("Error writing message to client: ", "error", err.Error())
+				c.Close(websocket.CloseGoingAway, "")
+				return
+			}
+		case <-c.done:
+			return
 		}
 	}
 }
 
 func (h *Hub) readPump(c *WSClient) {
+	// Connections that stop answering pings are closed once the read deadline expires
+	c.conn.SetReadDeadline(time.Now().Add(config.WSPongTimeout))
+	c.conn.SetPongHandler(func(appData string) error {
+		c.conn.SetReadDeadline(time.Now().Add(config.WSPongTimeout))
+		h.recordLatency(c, appData)
+		return nil
+	})
+
 	for {
 		msgType, msgData, err := c.conn.ReadMessage()
 		if err != nil {
-			slog.Error("Error reading message: ", "error", err.Error())
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				slog.Error("Error reading message: ", "error", err.Error())
+			}
 			return
 		}
 
@@ -123,12 +185,31 @@ func (h *Hub) readPump(c *WSClient) {
 		err = proto.Unmarshal(msgData, message)
 		if err != nil {
 			sendError(c, msg.ErrorMsg, ErrDecodingMsg.Error())
+			continue
 		}
 
 		h.executeHandler(c, message)
 	}
 }
 
+// recordLatency stores the round-trip time of the ping that was answered and reports it to the client.
+func (h *Hub) recordLatency(c *WSClient, appData string) {
+	sentAt, err := strconv.ParseInt(appData, 10, 64)
+	if err != nil {
+		return
+	}
+
+	rtt := time.Since(time.Unix(0, sentAt))
+	if rtt < 0 {
+		return
+	}
+	c.latency.Store(int64(rtt))
+
+	sendMessage(c, msg.LatencyMsg, &msg.Latency{
+		RttMs: uint32(rtt.Milliseconds()),
+	})
+}
+
 func (h *Hub) executeHandler(client *WSClient, message *msg.WSMessage) {
 	handler, ok := h.handlers[message.Type]
 	if !ok {
@@ -140,6 +221,9 @@ func (h *Hub) executeHandler(client *WSClient, message *msg.WSMessage) {
 }
 
 func (h *Hub) waitForAuth(conn *websocket.Conn, providedUserID string) error {
+	conn.SetReadLimit(config.WSMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(config.WSAuthTimeout))
+
 	msgType, msgData, err := conn.ReadMessage()
 	if err != nil {
 		return err
@@ -184,18 +268,71 @@ func (h *Hub) addConnection(client *WSClient) {
 func (h *Hub) removeConnection(client *WSClient) {
 	slog.Info("connection closed", "ip", client.conn.RemoteAddr().String())
 
-	h.roomMutex.RLock()
-	for _, room := range h.rooms {
-		room.RemoveClient(client.ID)
+	client.mu.RLock()
+	rooms := make([]*Room, 0, len(client.joinedRooms))
+	for _, room := range client.joinedRooms {
+		rooms = append(rooms, room)
+	}
+	client.mu.RUnlock()
+
+	for _, room := range rooms {
+		h.removeFromRoom(client, room)
 	}
-	h.roomMutex.RUnlock()
 
-	close(client.send)
-	client.conn.Close()
+	client.Close(websocket.CloseNormalClosure, "")
 
+	// Only remove this connection, the user might still be connected from somewhere else
 	h.connMutex.Lock()
-	delete(h.conns, client.ID)
+	delete(h.conns[client.ID], client.connID)
+	lastConnection := len(h.conns[client.ID]) == 0
+	if lastConnection {
+		delete(h.conns, client.ID)
+	}
 	h.connMutex.Unlock()
+
+	if lastConnection {
+		h.clipboardMutex.Lock()
+		delete(h.clipboards, client.ID)
+		h.clipboardMutex.Unlock()
+	}
+}
+
+// SendStats returns the counters of messages that could not be delivered to slow or disconnected clients.
+func (h *Hub) SendStats() *SendStats {
+	return &h.sendStats
+}
+
+func (h *Hub) getRoom(roomID string) *Room {
+	h.roomMutex.RLock()
+	defer h.roomMutex.RUnlock()
+
+	return h.rooms[roomID]
+}
+
+// lockCanvas locks the stored layers of the canvas so that its room can't load them while they are being replaced.
+// Returns the function that releases the lock.
+func (h *Hub) lockCanvas(canvasID string) func() {
+	h.canvasLockMutex.Lock()
+	lock, ok := h.canvasLocks[canvasID]
+	if !ok {
+		lock = &canvasLock{}
+		h.canvasLocks[canvasID] = lock
+	}
+	lock.refs++
+	h.canvasLockMutex.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+
+		h.canvasLockMutex.Lock()
+		defer h.canvasLockMutex.Unlock()
+
+		lock.refs--
+		if lock.refs == 0 {
+			delete(h.canvasLocks, canvasID)
+		}
+	}
 }
 
 func (h *Hub) getClient(userID, connID string) (*WSClient, bool) {
@@ -0,0 +1,34 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockCanvas(t *testing.T) {
+	h := &Hub{canvasLocks: make(map[string]*canvasLock)}
+
+	unlock := h.lockCanvas("canvas")
+	unlockOther := h.lockCanvas("other")
+
+	locked := make(chan func())
+	go func() {
+		locked <- h.lockCanvas("canvas")
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("lockCanvas() returned while the canvas was locked")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	unlock()
+	unlockWaiting := <-locked
+	unlockWaiting()
+	unlockOther()
+
+	// Locks are removed once nobody holds or waits for them
+	if len(h.canvasLocks) != 0 {
+		t.Errorf("%d canvas locks left after unlocking", len(h.canvasLocks))
+	}
+}
@@ -20,30 +20,140 @@ create table canvases (
     link_access_role int not null,
     created_at timestamptz default now(),
     star_count int not null default 0,
+    palette bytea not null default '',
+    palette_locked boolean not null default false,
+    viewer_chat boolean not null default true,
 
     foreign key (owner_id) references users(user_id)
 );
 
+create table layers (
+    layer_id char(26) primary key,
+    canvas_id char(26) not null,
+    name varchar(32) not null,
+    position int not null,
+    visible boolean not null default true,
+    opacity int not null default 100,
+    locked boolean not null default false,
+    created_at timestamptz default now(),
+
+    foreign key (canvas_id) references canvases(canvas_id) on delete cascade
+);
+
+-- Pixels of each layer split in 64x64 tiles, fully transparent tiles are not stored
+create table layer_tiles (
+    layer_id char(26) not null,
+    tile_x int not null,
+    tile_y int not null,
+    data bytea not null,
+
+    primary key (layer_id, tile_x, tile_y),
+    foreign key (layer_id) references layers(layer_id) on delete cascade
+);
+
+-- Flattened image of the canvas split in 64x64 tiles, fully transparent tiles are not stored
+create table canvas_tiles (
+    canvas_id char(26) not null,
+    tile_x int not null,
+    tile_y int not null,
+    data bytea not null,
+
+    primary key (canvas_id, tile_x, tile_y),
+    foreign key (canvas_id) references canvases(canvas_id) on delete cascade
+);
+
+create table chat_messages (
+    message_id char(26) primary key,
+    canvas_id char(26) not null,
+    user_id char(26) not null,
+    content varchar(500) not null,
+    created_at timestamptz not null default now(),
+
+    foreign key (canvas_id) references canvases(canvas_id) on delete cascade,
+    foreign key (user_id) references users(user_id)
+);
+
+create index chat_messages_canvas_idx on chat_messages (canvas_id, message_id);
+
+create table comments (
+    comment_id char(26) primary key,
+    canvas_id char(26) not null,
+    parent_id char(26),
+    user_id char(26) not null,
+    content varchar(1000) not null,
+    x int not null,
+    y int not null,
+    width int not null default 0,
+    height int not null default 0,
+    resolved boolean not null default false,
+    resolved_by char(26),
+    resolved_at timestamptz,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now(),
+
+    foreign key (canvas_id) references canvases(canvas_id) on delete cascade,
+    foreign key (parent_id) references comments(comment_id) on delete cascade,
+    foreign key (user_id) references users(user_id),
+    foreign key (resolved_by) references users(user_id)
+);
+
+create index comments_canvas_idx on comments (canvas_id, comment_id);
+
 create table versions (
     version_id char(26) primary key,
     canvas_id char(26) not null,
     data bytea not null,
     timestamp timestamptz not null default now(),
     edit_count int not null,
+    label varchar(64),
+    created_by char(26),
 
-    foreign key (canvas_id) references canvases(canvas_id)
+    foreign key (canvas_id) references canvases(canvas_id) on delete cascade,
+    foreign key (created_by) references users(user_id)
 );
 
-create table edits (
+create table version_layers (
     version_id char(26) not null,
+    layer_id char(26) not null,
+    name varchar(32) not null,
+    position int not null,
+    visible boolean not null,
+    opacity int not null,
+    locked boolean not null,
+    data bytea not null,
+
+    primary key (version_id, layer_id),
+    foreign key (version_id) references versions(version_id) on delete cascade
+);
+
+create table edits (
+    edit_id char(26) primary key,
+    canvas_id char(26) not null,
+    version_id char(26),
     user_id char(26) not null,
+    pixel_count int not null,
     data bytea not null,
     timestamp timestamptz not null default now(),
 
-    primary key (version_id, user_id),
+    foreign key (canvas_id) references canvases(canvas_id) on delete cascade,
+    foreign key (version_id) references versions(version_id) on delete set null,
     foreign key (user_id) references users(user_id)
 );
 
+create index edits_canvas_idx on edits (canvas_id, edit_id);
+
+create table canvas_ops (
+    seq bigserial primary key,
+    canvas_id char(26) not null,
+    keyframe boolean not null,
+    data bytea not null,
+    timestamp timestamptz not null,
+
+    foreign key (canvas_id) references canvases(canvas_id) on delete cascade
+);
+
+create index canvas_ops_canvas_idx on canvas_ops (canvas_id, seq);
+
 create type object_type as enum ('canvas', 'collection');
 
 create table user_access (